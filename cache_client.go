@@ -1,86 +1,331 @@
 package userclient
 
 import (
+	"context"
+	"errors"
 	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CacheClient struct {
-	client Client
-	cache  Cache
+	client  Client
+	cache   Cache
+	metrics Metrics
+	tp      trace.TracerProvider
+	logger  Logger
 }
 
 func NewCacheClient(client Client, cache Cache) *CacheClient {
 	return &CacheClient{
 		client: client,
 		cache:  cache,
+		logger: NopLogger,
+	}
+}
+
+// SetLogger sets logger of cache failures, nil disables logging
+func (c *CacheClient) SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger
 	}
+	c.logger = l
+}
+
+// SetMetrics enables reporting of cache hits and misses
+func (c *CacheClient) SetMetrics(m Metrics) {
+	c.metrics = m
+}
+
+// SetTracerProvider sets provider of cache lookup spans, the global provider is used by default
+func (c *CacheClient) SetTracerProvider(tp trace.TracerProvider) {
+	c.tp = tp
 }
 
 func (c *CacheClient) Authenticate(username string, password string) (string, error) {
-	return c.client.Authenticate(username, password)
+	return c.AuthenticateContext(context.Background(), username, password)
+}
+
+func (c *CacheClient) AuthenticateContext(ctx context.Context, username string, password string) (string, error) {
+	return c.client.AuthenticateContext(ctx, username, password)
+}
+
+func (c *CacheClient) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	return c.client.Login(ctx, username, password)
+}
+
+func (c *CacheClient) Refresh(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+	return c.client.Refresh(ctx, refreshToken)
 }
 
 func (c *CacheClient) Me(token string) (*User, error) {
+	return c.MeContext(context.Background(), token)
+}
+
+func (c *CacheClient) MeContext(ctx context.Context, token string) (*User, error) {
 	var user = &User{}
 	cacheKey := c.cacheKeyMe(token)
-	if err := c.cache.Get(cacheKey, user); err == nil {
+	if err := c.get(ctx, cacheKey, user); err == nil {
+		c.observeCache(MethodMe, true)
 		return user, nil
 	}
-	user, err := c.client.Me(token)
+	c.observeCache(MethodMe, false)
+	user, err := c.client.MeContext(ctx, token)
 	if err != nil {
 		return user, err
 	}
-	c.cache.Set(cacheKey, user)
+	c.set(ctx, cacheKey, user)
 	return user, nil
 }
 
 func (c *CacheClient) Logout(token string) error {
+	return c.LogoutContext(context.Background(), token)
+}
+
+func (c *CacheClient) LogoutContext(ctx context.Context, token string) error {
 	c.cleanUp(token)
-	return c.client.Logout(token)
+	return c.client.LogoutContext(ctx, token)
 }
 
 func (c *CacheClient) FindById(token, userId string) (*User, error) {
+	return c.FindByIdContext(context.Background(), token, userId)
+}
+
+func (c *CacheClient) FindByIdContext(ctx context.Context, token, userId string) (*User, error) {
 	var user = &User{}
 	cacheKey := c.cacheKeyFindById(token, userId)
-	if err := c.cache.Get(cacheKey, user); err == nil {
+	if err := c.get(ctx, cacheKey, user); err == nil {
+		c.observeCache(MethodFindById, true)
 		return user, nil
 	}
-	user, err := c.client.FindById(token, userId)
+	c.observeCache(MethodFindById, false)
+	user, err := c.client.FindByIdContext(ctx, token, userId)
 	if err != nil {
 		return user, err
 	}
-	c.cache.Set(cacheKey, user)
+	c.setStored(ctx, token, cacheKey, user)
 	return user, nil
 }
 
+// FindByIds returns cached users and requests only missing ones.
+// Users found by the batch request may lack platforms, so they're cached apart from FindById results
+func (c *CacheClient) FindByIds(ctx context.Context, token string, userIds []string) (map[string]*User, error) {
+	users := make(map[string]*User, len(userIds))
+	var missed []string
+	for _, id := range userIds {
+		if _, ok := users[id]; ok {
+			continue
+		}
+		user := &User{}
+		hit := c.get(ctx, c.cacheKeyFindById(token, id), user) == nil ||
+			c.get(ctx, c.cacheKeyFindByIds(token, id), user) == nil
+		if !hit {
+			user = nil
+			missed = append(missed, id)
+		}
+		c.observeCache(MethodFindByIds, hit)
+		users[id] = user
+	}
+
+	if len(missed) == 0 {
+		return users, nil
+	}
+
+	found, err := c.client.FindByIds(ctx, token, missed)
+	var platformsErr *PlatformsError
+	if err != nil && !errors.As(err, &platformsErr) {
+		return nil, err
+	}
+	for _, id := range missed {
+		user := found[id]
+		// users which platforms couldn't be fetched aren't cached
+		failed := platformsErr != nil && platformsErr.Errors[id] != nil
+		if user != nil && !failed {
+			c.setStored(ctx, token, c.cacheKeyFindByIds(token, id), user)
+		}
+		users[id] = user
+	}
+
+	return users, err
+}
+
 func (c *CacheClient) FindAll(token string) ([]*User, error) {
-	return c.client.FindAll(token)
+	return c.FindAllContext(context.Background(), token)
+}
+
+func (c *CacheClient) FindAllContext(ctx context.Context, token string) ([]*User, error) {
+	return c.client.FindAllContext(ctx, token)
+}
+
+func (c *CacheClient) ListUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+	return c.client.ListUsers(ctx, token, params)
+}
+
+func (c *CacheClient) SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error) {
+	return c.client.SearchUsers(ctx, token, filter, params)
 }
 
 func (c *CacheClient) RevokedTokens(token string) ([]RevokedToken, error) {
-	return c.client.RevokedTokens(token)
+	return c.RevokedTokensContext(context.Background(), token)
 }
 
-func (c *CacheClient) cacheKeyMe(token string) string {
-	return fmt.Sprintf("user-middleware/%s/me", token)
+func (c *CacheClient) RevokedTokensContext(ctx context.Context, token string) ([]RevokedToken, error) {
+	return c.client.RevokedTokensContext(ctx, token)
 }
 
-func (c *CacheClient) cacheKeyFindById(token, userId string) string {
-	key := fmt.Sprintf("user-middleware/%s/me/%s", token, userId)
+func (c *CacheClient) CreateUser(ctx context.Context, token string, input CreateUserInput) (*User, error) {
+	return c.client.CreateUser(ctx, token, input)
+}
+
+func (c *CacheClient) UpdateUser(ctx context.Context, token, userId string, input UpdateUserInput) (*User, error) {
+	user, err := c.client.UpdateUser(ctx, token, userId, input)
+	if err == nil {
+		c.invalidateUser(token, userId)
+	}
+	return user, err
+}
+
+func (c *CacheClient) PatchUser(ctx context.Context, token, userId string, input PatchUserInput) (*User, error) {
+	user, err := c.client.PatchUser(ctx, token, userId, input)
+	if err == nil {
+		c.invalidateUser(token, userId)
+	}
+	return user, err
+}
+
+func (c *CacheClient) DeactivateUser(ctx context.Context, token, userId string) error {
+	err := c.client.DeactivateUser(ctx, token, userId)
+	if err == nil {
+		c.invalidateUser(token, userId)
+	}
+	return err
+}
+
+func (c *CacheClient) DeleteUser(ctx context.Context, token, userId string) error {
+	err := c.client.DeleteUser(ctx, token, userId)
+	if err == nil {
+		c.invalidateUser(token, userId)
+	}
+	return err
+}
+
+func (c *CacheClient) GrantRoles(ctx context.Context, token, userId string, roles ...string) error {
+	err := c.client.GrantRoles(ctx, token, userId, roles...)
+	c.invalidateAssignments(token, userId, err)
+	return err
+}
+
+func (c *CacheClient) RevokeRoles(ctx context.Context, token, userId string, roles ...string) error {
+	err := c.client.RevokeRoles(ctx, token, userId, roles...)
+	c.invalidateAssignments(token, userId, err)
+	return err
+}
+
+func (c *CacheClient) AttachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	err := c.client.AttachPlatforms(ctx, token, userId, platformNames...)
+	c.invalidateAssignments(token, userId, err)
+	return err
+}
+
+func (c *CacheClient) DetachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	err := c.client.DetachPlatforms(ctx, token, userId, platformNames...)
+	c.invalidateAssignments(token, userId, err)
+	return err
+}
+
+func (c *CacheClient) ChangePassword(ctx context.Context, token string, input ChangePasswordInput) error {
+	return c.client.ChangePassword(ctx, token, input)
+}
+
+func (c *CacheClient) RequestPasswordReset(ctx context.Context, input PasswordResetInput) error {
+	return c.client.RequestPasswordReset(ctx, input)
+}
+
+func (c *CacheClient) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	return c.client.ConfirmPasswordReset(ctx, input)
+}
+
+// get reads the cache within a span, keys aren't recorded as they contain tokens
+func (c *CacheClient) get(ctx context.Context, key string, obj interface{}) error {
+	_, span := tracer(c.tp).Start(ctx, "userclient.cache.get")
+	err := c.cache.Get(key, obj)
+	span.SetAttributes(AttributeCacheHit.Bool(err == nil))
+	span.End()
+	return err
+}
+
+func (c *CacheClient) set(ctx context.Context, key string, obj interface{}) error {
+	_, span := tracer(c.tp).Start(ctx, "userclient.cache.set")
+	err := c.cache.Set(key, obj)
+	endSpan(span, err)
+	if err != nil {
+		c.logger.Warn("user cache write failed", Fields{FieldError: err.Error()})
+	}
+	return err
+}
+
+// setStored caches obj and remembers the key to delete it on logout
+func (c *CacheClient) setStored(ctx context.Context, token, key string, obj interface{}) {
+	if c.set(ctx, key, obj) != nil {
+		return
+	}
 	storedKeysKey := c.cacheKeyStoredKeys(token)
 	var keys []string
-	if err := c.cache.Get(storedKeysKey, keys); err != nil {
+	if err := c.cache.Get(storedKeysKey, &keys); err != nil {
 		keys = []string{}
 	}
+	for _, k := range keys {
+		if k == key {
+			return
+		}
+	}
 	keys = append(keys, key)
 	c.cache.Set(storedKeysKey, keys)
-	return key
+}
+
+func (c *CacheClient) observeCache(method string, hit bool) {
+	if c.metrics != nil {
+		c.metrics.ObserveCache(method, hit)
+	}
+}
+
+func (c *CacheClient) cacheKeyMe(token string) string {
+	return fmt.Sprintf("user-middleware/%s/me", token)
+}
+
+func (c *CacheClient) cacheKeyFindById(token, userId string) string {
+	return fmt.Sprintf("user-middleware/%s/me/%s", token, userId)
+}
+
+func (c *CacheClient) cacheKeyFindByIds(token, userId string) string {
+	return fmt.Sprintf("user-middleware/%s/batch/%s", token, userId)
 }
 
 func (c *CacheClient) cacheKeyStoredKeys(token string) string {
 	return fmt.Sprintf("user-middleware/%s/stored-keys", token)
 }
 
+// invalidateUser deletes the user cached for the token,
+// entries cached for other tokens expire with their TTL
+func (c *CacheClient) invalidateUser(token, userId string) {
+	c.cache.Delete(c.cacheKeyFindById(token, userId))
+	c.cache.Delete(c.cacheKeyFindByIds(token, userId))
+
+	var me User
+	if err := c.cache.Get(c.cacheKeyMe(token), &me); err == nil && me.Id == userId {
+		c.cache.Delete(c.cacheKeyMe(token))
+	}
+}
+
+// invalidateAssignments deletes the user after roles or platforms are changed,
+// AssignmentError means some of them could be changed before the failure
+func (c *CacheClient) invalidateAssignments(token, userId string, err error) {
+	var assignmentErr *AssignmentError
+	if err == nil || errors.As(err, &assignmentErr) {
+		c.invalidateUser(token, userId)
+	}
+}
+
 func (c *CacheClient) cleanUp(token string) {
 	var keys []string
 	c.cache.Delete(c.cacheKeyMe(token))
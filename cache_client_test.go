@@ -1,7 +1,10 @@
 package userclient
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -59,28 +62,220 @@ func TestUserCacheClient_FindById(t *testing.T) {
 	}
 }
 
+func TestUserCacheClient_FindByIds(t *testing.T) {
+	var requested [][]string
+	uncachedClient := &UserClientMock{
+		FindByIdsMock: func(ctx context.Context, token string, ids []string) (map[string]*User, error) {
+			requested = append(requested, ids)
+			users := make(map[string]*User)
+			for _, id := range ids {
+				users[id] = nil
+				if id != "missing" {
+					users[id] = &User{Id: id}
+				}
+			}
+			return users, nil
+		},
+	}
+	cache := newCachedMock()
+
+	client := NewCacheClient(uncachedClient, cache)
+	users, err := client.FindByIds(context.Background(), "token", []string{"1", "missing"})
+	if err != nil {
+		t.Error("Cached client returned error")
+	}
+	if users["1"] == nil || users["missing"] != nil {
+		t.Errorf("Wrong users returned: %v", users)
+	}
+
+	users, err = client.FindByIds(context.Background(), "token", []string{"1", "2", "missing"})
+	if err != nil {
+		t.Error("Cached client returned error")
+	}
+	if len(users) != 3 || users["2"] == nil {
+		t.Errorf("Wrong users returned: %v", users)
+	}
+	if !reflect.DeepEqual(requested, [][]string{{"1", "missing"}, {"2", "missing"}}) {
+		t.Errorf("Only cache misses should be requested, requested %v", requested)
+	}
+}
+
+func TestUserCacheClient_FindByIdsDoesNotServeFindById(t *testing.T) {
+	calls := 0
+	uncachedClient := &UserClientMock{
+		FindByIdsMock: func(ctx context.Context, token string, ids []string) (map[string]*User, error) {
+			// batch results come without platforms
+			return map[string]*User{"1": {Id: "1"}}, nil
+		},
+		FindByIdContextMock: func(ctx context.Context, token, id string) (*User, error) {
+			calls++
+			return &User{Id: id, PlatformNames: []string{"ALIBABA"}}, nil
+		},
+		LogoutMock: func(token string) error {
+			return nil
+		},
+	}
+	cache := newCachedMock()
+
+	client := NewCacheClient(uncachedClient, cache)
+	client.FindByIds(context.Background(), "token", []string{"1"})
+	client.FindByIds(context.Background(), "token", []string{"1"})
+	user, err := client.FindById("token", "1")
+	if err != nil {
+		t.Fatal("Cached client returned error")
+	}
+	if calls != 1 || !reflect.DeepEqual(user.PlatformNames, []string{"ALIBABA"}) {
+		t.Errorf("FindById should request the user with platforms, got %+v", user)
+	}
+	client.FindById("token", "1")
+
+	var keys []string
+	cache.Get(client.cacheKeyStoredKeys("token"), &keys)
+	if len(keys) != 2 {
+		t.Errorf("Each cached key should be stored once, got %v", keys)
+	}
+
+	client.Logout("token")
+	if len(cache.items) != 0 {
+		t.Errorf("Logout should clean up the cache, left %d items", len(cache.items))
+	}
+}
+
+func TestUserCacheClient_FindByIdsPlatformsError(t *testing.T) {
+	calls := 0
+	uncachedClient := &UserClientMock{
+		FindByIdsMock: func(ctx context.Context, token string, ids []string) (map[string]*User, error) {
+			calls++
+			users := make(map[string]*User)
+			errs := make(map[string]error)
+			for _, id := range ids {
+				users[id] = &User{Id: id, PlatformNames: []string{}}
+				if id == "2" {
+					users[id].PlatformNames = nil
+					errs[id] = ErrServiceUnavailable
+				}
+			}
+			if len(errs) > 0 {
+				return users, &PlatformsError{Errors: errs}
+			}
+			return users, nil
+		},
+	}
+	cache := newCachedMock()
+
+	client := NewCacheClient(uncachedClient, cache)
+	client.FindByIds(context.Background(), "token", []string{"1"})
+	users, err := client.FindByIds(context.Background(), "token", []string{"1", "2"})
+	var platformsErr *PlatformsError
+	if !errors.As(err, &platformsErr) {
+		t.Errorf("Platforms error should be returned, got %v", err)
+	}
+	if len(users) != 2 || users["1"] == nil || users["2"] == nil {
+		t.Errorf("Users should be returned along with the error, got %v", users)
+	}
+
+	client.FindByIds(context.Background(), "token", []string{"1", "2"})
+	if calls != 3 {
+		t.Errorf("User with failed platforms shouldn't be cached, client called %d times", calls)
+	}
+}
+
+func TestUserCacheClient_WritesInvalidateUser(t *testing.T) {
+	calls := 0
+	uncachedClient := &UserClientMock{
+		FindByIdMock: func(token, id string) (*User, error) {
+			calls++
+			return &User{Id: id, Active: true}, nil
+		},
+		DeactivateUserMock: func(ctx context.Context, token, userId string) error {
+			return nil
+		},
+		DeleteUserMock: func(ctx context.Context, token, userId string) error {
+			return errors.New("delete failed")
+		},
+	}
+
+	client := NewCacheClient(uncachedClient, newCachedMock())
+	client.FindById("token", "1")
+	client.DeleteUser(context.Background(), "token", "1")
+	client.FindById("token", "1")
+	if calls != 1 {
+		t.Errorf("Failed write shouldn't invalidate cache, client called %d times", calls)
+	}
+
+	client.DeactivateUser(context.Background(), "token", "1")
+	client.FindById("token", "1")
+	if calls != 2 {
+		t.Errorf("User should be requested after deactivation, client called %d times", calls)
+	}
+}
+
+func TestUserCacheClient_AssignmentsInvalidateUser(t *testing.T) {
+	calls := 0
+	uncachedClient := &UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			calls++
+			return &User{Id: "1"}, nil
+		},
+		GrantRolesMock: func(ctx context.Context, token, userId string, roles ...string) error {
+			return nil
+		},
+	}
+
+	client := NewCacheClient(uncachedClient, newCachedMock())
+	client.Me("token")
+	client.GrantRoles(context.Background(), "token", "1", RoleAdmin)
+	client.Me("token")
+	if calls != 2 {
+		t.Errorf("User should be requested after roles are granted, client called %d times", calls)
+	}
+}
+
+func TestUserCacheClient_Metrics(t *testing.T) {
+	metrics := newMetricsMock()
+	client := NewCacheClient(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return &User{}, nil
+		},
+	}, newCachedMock())
+	client.SetMetrics(metrics)
+
+	client.Me("token")
+	client.Me("token")
+	client.Me("token")
+
+	if metrics.hits[MethodMe] != 2 || metrics.misses[MethodMe] != 1 {
+		t.Errorf("Wrong cache metrics: %d hits, %d misses", metrics.hits[MethodMe], metrics.misses[MethodMe])
+	}
+}
+
 type cacheMock struct {
-	items map[string]interface{}
+	items map[string][]byte
 }
 
 func newCachedMock() *cacheMock {
-	return &cacheMock{items: make(map[string]interface{})}
+	return &cacheMock{items: make(map[string][]byte)}
 }
 
 func (c *cacheMock) Get(key string, obj interface{}) error {
-	_, ok := c.items[key]
+	data, ok := c.items[key]
 	if !ok {
 		return errors.New("not found")
 	}
-	return nil
+	return json.Unmarshal(data, obj)
 }
 
 func (c *cacheMock) Set(key string, obj interface{}) error {
-	c.items[key] = obj
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	c.items[key] = data
 	return nil
 }
 
 func (c *cacheMock) Delete(key string) error {
+	delete(c.items, key)
 	return nil
 }
 
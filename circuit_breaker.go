@@ -0,0 +1,220 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of CircuitBreakerClient
+type CircuitState int
+
+const (
+	// StateClosed lets all calls through
+	StateClosed CircuitState = iota
+	// StateOpen fails all calls fast with ErrCircuitOpen
+	StateOpen
+	// StateHalfOpen lets a limited number of trial calls through
+	StateHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+type CircuitBreakerConfig struct {
+	// Window is the period failures are counted over in closed state
+	Window time.Duration
+	// MinRequests is the minimal number of calls in the window to open the breaker
+	MinRequests int
+	// FailureRatio opens the breaker when the ratio of failed calls in the window reaches it
+	FailureRatio float64
+	// CoolDown is the time the breaker stays open before letting trial calls through
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of successful trial calls closing the breaker
+	HalfOpenRequests int
+}
+
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	Window:           10 * time.Second,
+	MinRequests:      10,
+	FailureRatio:     0.5,
+	CoolDown:         5 * time.Second,
+	HalfOpenRequests: 1,
+}
+
+// CircuitBreakerClient fails fast with ErrCircuitOpen while user service is failing.
+// Only errors meaning the service is unavailable are counted as failures, see IsRetryable,
+// rate limiting and errors caused by the caller's context aren't.
+type CircuitBreakerClient struct {
+	*interceptedClient
+
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu sync.Mutex
+	// generation changes with every state change or window rollover,
+	// results of calls started in another generation are ignored
+	generation  uint64
+	state       CircuitState
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	inFlight    int
+	successes   int
+}
+
+func NewCircuitBreakerClient(c ContextClient, config CircuitBreakerConfig) *CircuitBreakerClient {
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	b := &CircuitBreakerClient{
+		config: config,
+		now:    time.Now,
+	}
+	b.interceptedClient = &interceptedClient{client: c, intercept: b.intercept}
+	b.windowStart = b.now()
+	return b
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreakerClient) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+	return b.state
+}
+
+func (b *CircuitBreakerClient) intercept(ctx context.Context, method string, call func(ctx context.Context) error) error {
+	generation, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = call(ctx)
+	b.record(generation, callOutcome(ctx, err))
+	return err
+}
+
+// outcome is the result of a call as seen by the breaker
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored tells nothing about user service health
+	outcomeIgnored
+)
+
+// callOutcome classifies err, errors caused by the caller's context and rate limiting are ignored
+func callOutcome(ctx context.Context, err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return outcomeIgnored
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+		return outcomeIgnored
+	}
+
+	if IsRetryable(err) {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+func (b *CircuitBreakerClient) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+	switch b.state {
+	case StateOpen:
+		return 0, ErrCircuitOpen
+	case StateHalfOpen:
+		if b.inFlight >= b.config.HalfOpenRequests-b.successes {
+			return 0, ErrCircuitOpen
+		}
+		b.inFlight++
+	}
+
+	return b.generation, nil
+}
+
+func (b *CircuitBreakerClient) record(generation uint64, result outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refresh(now)
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case StateClosed:
+		if result == outcomeIgnored {
+			return
+		}
+		b.requests++
+		if result == outcomeFailure {
+			b.failures++
+		}
+		if b.failures > 0 && b.requests >= b.config.MinRequests && float64(b.failures) >= b.config.FailureRatio*float64(b.requests) {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+		switch result {
+		case outcomeIgnored:
+			return
+		case outcomeFailure:
+			b.setState(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// refresh moves the breaker to half-open state after cool-down and rolls the window over
+func (b *CircuitBreakerClient) refresh(now time.Time) {
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) >= b.config.CoolDown {
+			b.setState(StateHalfOpen, now)
+		}
+	case StateClosed:
+		if b.config.Window > 0 && now.Sub(b.windowStart) >= b.config.Window {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+func (b *CircuitBreakerClient) setState(state CircuitState, now time.Time) {
+	b.generation++
+	b.state = state
+	b.requests, b.failures = 0, 0
+	b.inFlight, b.successes = 0, 0
+	b.windowStart = now
+	if state == StateOpen {
+		b.openedAt = now
+	}
+}
@@ -0,0 +1,184 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestCircuitBreaker(meErr *error, calls *int) (*CircuitBreakerClient, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	client := &UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			*calls++
+			if *meErr != nil {
+				return nil, *meErr
+			}
+			return &User{}, nil
+		},
+	}
+	breaker := NewCircuitBreakerClient(client, CircuitBreakerConfig{
+		Window:           time.Minute,
+		MinRequests:      4,
+		FailureRatio:     0.5,
+		CoolDown:         time.Second,
+		HalfOpenRequests: 1,
+	})
+	breaker.now = clock.Now
+	breaker.windowStart = clock.Now()
+	return breaker, clock
+}
+
+func TestCircuitBreaker_Opens(t *testing.T) {
+	var meErr error
+	calls := 0
+	breaker, _ := newTestCircuitBreaker(&meErr, &calls)
+
+	breaker.Me("token")
+	breaker.Me("token")
+	meErr = ErrServiceUnavailable
+	breaker.Me("token")
+	if breaker.State() != StateClosed {
+		t.Fatalf("breaker should be closed before min requests, it's %s", breaker.State())
+	}
+	breaker.Me("token")
+	if breaker.State() != StateOpen {
+		t.Fatalf("breaker should be open, it's %s", breaker.State())
+	}
+
+	_, err := breaker.Me("token")
+	if err != ErrCircuitOpen {
+		t.Errorf("error should be ErrCircuitOpen, got %v", err)
+	}
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Error("ErrCircuitOpen should match ErrServiceUnavailable")
+	}
+	if calls != 4 {
+		t.Errorf("client shouldn't be called when breaker is open, called %d times", calls)
+	}
+}
+
+func TestCircuitBreaker_IgnoresClientErrors(t *testing.T) {
+	meErr := ErrUnauthorized
+	calls := 0
+	breaker, _ := newTestCircuitBreaker(&meErr, &calls)
+
+	for i := 0; i < 10; i++ {
+		breaker.Me("token")
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("breaker should stay closed, it's %s", breaker.State())
+	}
+}
+
+func TestCircuitBreaker_IgnoresCallerErrors(t *testing.T) {
+	meErr := ErrServiceUnavailable
+	calls := 0
+	breaker, _ := newTestCircuitBreaker(&meErr, &calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		breaker.MeContext(ctx, "token")
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("breaker should stay closed on caller's deadline, it's %s", breaker.State())
+	}
+
+	meErr = &APIError{StatusCode: http.StatusTooManyRequests, err: parseToError(http.StatusTooManyRequests)}
+	for i := 0; i < 10; i++ {
+		breaker.Me("token")
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("breaker should stay closed on rate limiting, it's %s", breaker.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	meErr := ErrServiceUnavailable
+	calls := 0
+	breaker, clock := newTestCircuitBreaker(&meErr, &calls)
+
+	for i := 0; i < 4; i++ {
+		breaker.MeContext(context.Background(), "token")
+	}
+	if breaker.State() != StateOpen {
+		t.Fatalf("breaker should be open, it's %s", breaker.State())
+	}
+
+	clock.Add(time.Second)
+	if breaker.State() != StateHalfOpen {
+		t.Fatalf("breaker should be half-open after cool-down, it's %s", breaker.State())
+	}
+
+	// failed trial opens the breaker again
+	breaker.Me("token")
+	if breaker.State() != StateOpen {
+		t.Fatalf("breaker should be open after failed trial, it's %s", breaker.State())
+	}
+
+	clock.Add(time.Second)
+	meErr = nil
+	if _, err := breaker.Me("token"); err != nil {
+		t.Errorf("trial call shouldn't fail, got %v", err)
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("breaker should be closed after successful trial, it's %s", breaker.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenIgnoresCanceledTrial(t *testing.T) {
+	meErr := ErrServiceUnavailable
+	calls := 0
+	breaker, clock := newTestCircuitBreaker(&meErr, &calls)
+
+	for i := 0; i < 4; i++ {
+		breaker.Me("token")
+	}
+	clock.Add(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	breaker.MeContext(ctx, "token")
+	if breaker.State() != StateHalfOpen {
+		t.Fatalf("canceled trial shouldn't change the state, it's %s", breaker.State())
+	}
+
+	// the slot of the canceled trial is released
+	breaker.Me("token")
+	if calls != 6 {
+		t.Errorf("another trial should be allowed, client called %d times", calls)
+	}
+	if breaker.State() != StateOpen {
+		t.Errorf("breaker should be open after failed trial, it's %s", breaker.State())
+	}
+}
+
+func TestCircuitBreaker_WindowRollover(t *testing.T) {
+	meErr := ErrServiceUnavailable
+	calls := 0
+	breaker, clock := newTestCircuitBreaker(&meErr, &calls)
+
+	for i := 0; i < 3; i++ {
+		breaker.Me("token")
+	}
+	clock.Add(time.Minute)
+	breaker.Me("token")
+	if breaker.State() != StateClosed {
+		t.Errorf("failures of the previous window shouldn't be counted, breaker is %s", breaker.State())
+	}
+}
@@ -1,6 +1,55 @@
 package userclient
 
+import "context"
+
+// ContextClient is the context-first user service client.
+// The context controls cancellation and deadlines of the underlying calls.
+type ContextClient interface {
+	AuthenticateContext(ctx context.Context, username, password string) (string, error)
+	// Login authenticates the user like AuthenticateContext and returns refresh token and expiry as well
+	Login(ctx context.Context, username, password string) (*LoginResponse, error)
+	// Refresh exchanges the refresh token for a new access token
+	Refresh(ctx context.Context, refreshToken string) (*LoginResponse, error)
+
+	MeContext(ctx context.Context, token string) (*User, error)
+	LogoutContext(ctx context.Context, token string) error
+
+	FindByIdContext(ctx context.Context, token, userId string) (*User, error)
+	// FindByIds returns users keyed by id, ids of not found users are mapped to nil
+	FindByIds(ctx context.Context, token string, userIds []string) (map[string]*User, error)
+	FindAllContext(ctx context.Context, token string) ([]*User, error)
+	ListUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error)
+	SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error)
+
+	RevokedTokensContext(ctx context.Context, token string) ([]RevokedToken, error)
+
+	CreateUser(ctx context.Context, token string, input CreateUserInput) (*User, error)
+	UpdateUser(ctx context.Context, token, userId string, input UpdateUserInput) (*User, error)
+	PatchUser(ctx context.Context, token, userId string, input PatchUserInput) (*User, error)
+	DeactivateUser(ctx context.Context, token, userId string) error
+	DeleteUser(ctx context.Context, token, userId string) error
+
+	// GrantRoles and AttachPlatforms succeed for already assigned roles and platforms,
+	// RevokeRoles and DetachPlatforms succeed for not assigned ones
+	GrantRoles(ctx context.Context, token, userId string, roles ...string) error
+	RevokeRoles(ctx context.Context, token, userId string, roles ...string) error
+	AttachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error
+	DetachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error
+
+	// ChangePassword changes password of the user the token belongs to
+	ChangePassword(ctx context.Context, token string, input ChangePasswordInput) error
+	// RequestPasswordReset sends reset token to the user, it doesn't require authentication
+	RequestPasswordReset(ctx context.Context, input PasswordResetInput) error
+	// ConfirmPasswordReset sets new password using the reset token
+	ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error
+}
+
+// Client is the user service client.
+// Methods without context are kept for backward compatibility
+// and behave like their context counterparts called with context.Background().
 type Client interface {
+	ContextClient
+
 	Authenticate(username, password string) (string, error)
 
 	Me(token string) (*User, error)
@@ -11,3 +60,37 @@ type Client interface {
 
 	RevokedTokens(token string) ([]RevokedToken, error)
 }
+
+// NewClientAdapter returns Client on top of ContextClient,
+// methods without context are called with context.Background()
+func NewClientAdapter(c ContextClient) Client {
+	return &clientAdapter{c}
+}
+
+type clientAdapter struct {
+	ContextClient
+}
+
+func (a *clientAdapter) Authenticate(username, password string) (string, error) {
+	return a.AuthenticateContext(context.Background(), username, password)
+}
+
+func (a *clientAdapter) Me(token string) (*User, error) {
+	return a.MeContext(context.Background(), token)
+}
+
+func (a *clientAdapter) Logout(token string) error {
+	return a.LogoutContext(context.Background(), token)
+}
+
+func (a *clientAdapter) FindById(token, userId string) (*User, error) {
+	return a.FindByIdContext(context.Background(), token, userId)
+}
+
+func (a *clientAdapter) FindAll(token string) ([]*User, error) {
+	return a.FindAllContext(context.Background(), token)
+}
+
+func (a *clientAdapter) RevokedTokens(token string) ([]RevokedToken, error) {
+	return a.RevokedTokensContext(context.Background(), token)
+}
@@ -1,5 +1,10 @@
 package userclient
 
+import "context"
+
+// UserClientMock is a Client with replaceable methods.
+// Context methods fall back to the mocks without context when their own mock isn't set,
+// and vice versa.
 type UserClientMock struct {
 	AuthenticateMock func(username string, password string) (string, error)
 	MeMock           func(token string) (*User, error)
@@ -8,28 +13,194 @@ type UserClientMock struct {
 	FindByIdMock      func(token, userId string) (*User, error)
 	FindAllMock       func(token string) ([]*User, error)
 	RevokedTokensMock func(token string) ([]RevokedToken, error)
+
+	AuthenticateContextMock func(ctx context.Context, username string, password string) (string, error)
+	MeContextMock           func(ctx context.Context, token string) (*User, error)
+	LogoutContextMock       func(ctx context.Context, token string) error
+
+	FindByIdContextMock      func(ctx context.Context, token, userId string) (*User, error)
+	FindAllContextMock       func(ctx context.Context, token string) ([]*User, error)
+	RevokedTokensContextMock func(ctx context.Context, token string) ([]RevokedToken, error)
+
+	// LoginMock falls back to the authenticate mocks, RefreshMock has no fallback
+	LoginMock   func(ctx context.Context, username, password string) (*LoginResponse, error)
+	RefreshMock func(ctx context.Context, refreshToken string) (*LoginResponse, error)
+
+	FindByIdsMock   func(ctx context.Context, token string, userIds []string) (map[string]*User, error)
+	ListUsersMock   func(ctx context.Context, token string, params ListUsersParams) (*UserPage, error)
+	SearchUsersMock func(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error)
+
+	CreateUserMock     func(ctx context.Context, token string, input CreateUserInput) (*User, error)
+	UpdateUserMock     func(ctx context.Context, token, userId string, input UpdateUserInput) (*User, error)
+	PatchUserMock      func(ctx context.Context, token, userId string, input PatchUserInput) (*User, error)
+	DeactivateUserMock func(ctx context.Context, token, userId string) error
+	DeleteUserMock     func(ctx context.Context, token, userId string) error
+
+	GrantRolesMock      func(ctx context.Context, token, userId string, roles ...string) error
+	RevokeRolesMock     func(ctx context.Context, token, userId string, roles ...string) error
+	AttachPlatformsMock func(ctx context.Context, token, userId string, platformNames ...string) error
+	DetachPlatformsMock func(ctx context.Context, token, userId string, platformNames ...string) error
+
+	ChangePasswordMock       func(ctx context.Context, token string, input ChangePasswordInput) error
+	RequestPasswordResetMock func(ctx context.Context, input PasswordResetInput) error
+	ConfirmPasswordResetMock func(ctx context.Context, input ConfirmPasswordResetInput) error
 }
 
 func (c *UserClientMock) Authenticate(username string, password string) (string, error) {
+	if c.AuthenticateMock == nil {
+		return c.AuthenticateContextMock(context.Background(), username, password)
+	}
 	return c.AuthenticateMock(username, password)
 }
 
+func (c *UserClientMock) AuthenticateContext(ctx context.Context, username string, password string) (string, error) {
+	if c.AuthenticateContextMock == nil {
+		return c.AuthenticateMock(username, password)
+	}
+	return c.AuthenticateContextMock(ctx, username, password)
+}
+
+func (c *UserClientMock) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	if c.LoginMock == nil {
+		token, err := c.AuthenticateContext(ctx, username, password)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResponse{AccessToken: token}, nil
+	}
+	return c.LoginMock(ctx, username, password)
+}
+
+func (c *UserClientMock) Refresh(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+	return c.RefreshMock(ctx, refreshToken)
+}
+
 func (c *UserClientMock) Me(token string) (*User, error) {
+	if c.MeMock == nil {
+		return c.MeContextMock(context.Background(), token)
+	}
 	return c.MeMock(token)
 }
 
+func (c *UserClientMock) MeContext(ctx context.Context, token string) (*User, error) {
+	if c.MeContextMock == nil {
+		return c.MeMock(token)
+	}
+	return c.MeContextMock(ctx, token)
+}
+
 func (c *UserClientMock) Logout(token string) error {
+	if c.LogoutMock == nil {
+		return c.LogoutContextMock(context.Background(), token)
+	}
 	return c.LogoutMock(token)
 }
 
+func (c *UserClientMock) LogoutContext(ctx context.Context, token string) error {
+	if c.LogoutContextMock == nil {
+		return c.LogoutMock(token)
+	}
+	return c.LogoutContextMock(ctx, token)
+}
+
 func (c *UserClientMock) FindById(token, userId string) (*User, error) {
+	if c.FindByIdMock == nil {
+		return c.FindByIdContextMock(context.Background(), token, userId)
+	}
 	return c.FindByIdMock(token, userId)
 }
 
+func (c *UserClientMock) FindByIdContext(ctx context.Context, token, userId string) (*User, error) {
+	if c.FindByIdContextMock == nil {
+		return c.FindByIdMock(token, userId)
+	}
+	return c.FindByIdContextMock(ctx, token, userId)
+}
+
 func (c *UserClientMock) FindAll(token string) ([]*User, error) {
+	if c.FindAllMock == nil {
+		return c.FindAllContextMock(context.Background(), token)
+	}
 	return c.FindAllMock(token)
 }
 
+func (c *UserClientMock) FindAllContext(ctx context.Context, token string) ([]*User, error) {
+	if c.FindAllContextMock == nil {
+		return c.FindAllMock(token)
+	}
+	return c.FindAllContextMock(ctx, token)
+}
+
+func (c *UserClientMock) FindByIds(ctx context.Context, token string, userIds []string) (map[string]*User, error) {
+	return c.FindByIdsMock(ctx, token, userIds)
+}
+
+func (c *UserClientMock) ListUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+	return c.ListUsersMock(ctx, token, params)
+}
+
+func (c *UserClientMock) SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error) {
+	return c.SearchUsersMock(ctx, token, filter, params)
+}
+
 func (c *UserClientMock) RevokedTokens(token string) ([]RevokedToken, error) {
-	return c.RevokedTokens(token)
+	if c.RevokedTokensMock == nil {
+		return c.RevokedTokensContextMock(context.Background(), token)
+	}
+	return c.RevokedTokensMock(token)
+}
+
+func (c *UserClientMock) RevokedTokensContext(ctx context.Context, token string) ([]RevokedToken, error) {
+	if c.RevokedTokensContextMock == nil {
+		return c.RevokedTokensMock(token)
+	}
+	return c.RevokedTokensContextMock(ctx, token)
+}
+
+func (c *UserClientMock) CreateUser(ctx context.Context, token string, input CreateUserInput) (*User, error) {
+	return c.CreateUserMock(ctx, token, input)
+}
+
+func (c *UserClientMock) UpdateUser(ctx context.Context, token, userId string, input UpdateUserInput) (*User, error) {
+	return c.UpdateUserMock(ctx, token, userId, input)
+}
+
+func (c *UserClientMock) PatchUser(ctx context.Context, token, userId string, input PatchUserInput) (*User, error) {
+	return c.PatchUserMock(ctx, token, userId, input)
+}
+
+func (c *UserClientMock) DeactivateUser(ctx context.Context, token, userId string) error {
+	return c.DeactivateUserMock(ctx, token, userId)
+}
+
+func (c *UserClientMock) DeleteUser(ctx context.Context, token, userId string) error {
+	return c.DeleteUserMock(ctx, token, userId)
+}
+
+func (c *UserClientMock) GrantRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.GrantRolesMock(ctx, token, userId, roles...)
+}
+
+func (c *UserClientMock) RevokeRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.RevokeRolesMock(ctx, token, userId, roles...)
+}
+
+func (c *UserClientMock) AttachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.AttachPlatformsMock(ctx, token, userId, platformNames...)
+}
+
+func (c *UserClientMock) DetachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.DetachPlatformsMock(ctx, token, userId, platformNames...)
+}
+
+func (c *UserClientMock) ChangePassword(ctx context.Context, token string, input ChangePasswordInput) error {
+	return c.ChangePasswordMock(ctx, token, input)
+}
+
+func (c *UserClientMock) RequestPasswordReset(ctx context.Context, input PasswordResetInput) error {
+	return c.RequestPasswordResetMock(ctx, input)
+}
+
+func (c *UserClientMock) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	return c.ConfirmPasswordResetMock(ctx, input)
 }
@@ -0,0 +1,28 @@
+package userclient
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClientAdapter(t *testing.T) {
+	user := &User{}
+	var received context.Context
+	c := NewClientAdapter(&UserClientMock{
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			received = ctx
+			return user, nil
+		},
+	})
+
+	u, err := c.Me("token")
+	if err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if u != user {
+		t.Error("Me should return user")
+	}
+	if received != context.Background() {
+		t.Error("Me should be called with background context")
+	}
+}
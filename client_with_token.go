@@ -1,5 +1,10 @@
 package userclient
 
+import (
+	"context"
+	"errors"
+)
+
 type ClientWithToken interface {
 	Me() (*User, error)
 	FindById(userId string) (*User, error)
@@ -7,6 +12,14 @@ type ClientWithToken interface {
 	RevokedTokens() ([]RevokedToken, error)
 }
 
+// ContextClientWithToken is the context-first version of ClientWithToken
+type ContextClientWithToken interface {
+	MeContext(ctx context.Context) (*User, error)
+	FindByIdContext(ctx context.Context, userId string) (*User, error)
+	FindAllContext(ctx context.Context) ([]*User, error)
+	RevokedTokensContext(ctx context.Context) ([]RevokedToken, error)
+}
+
 type ClientWithTokenHolder struct {
 	client      Client
 	tokenHolder TokenHolder
@@ -17,49 +30,73 @@ func NewClientWithTokenHolder(c Client, h TokenHolder) *ClientWithTokenHolder {
 }
 
 func (c *ClientWithTokenHolder) Me() (*User, error) {
-	token, err := c.tokenHolder.GetToken(c.client)
+	return c.MeContext(context.Background())
+}
+
+func (c *ClientWithTokenHolder) MeContext(ctx context.Context) (*User, error) {
+	token, err := c.getToken(ctx)
 	if err != nil {
 		return nil, err
 	}
-	u, err := c.client.Me(token)
-	if err == ErrUnauthorized {
+	u, err := c.client.MeContext(ctx, token)
+	if errors.Is(err, ErrUnauthorized) {
 		c.tokenHolder.Invalidate()
 	}
 	return u, err
 }
 
 func (c *ClientWithTokenHolder) FindById(userId string) (*User, error) {
-	token, err := c.tokenHolder.GetToken(c.client)
+	return c.FindByIdContext(context.Background(), userId)
+}
+
+func (c *ClientWithTokenHolder) FindByIdContext(ctx context.Context, userId string) (*User, error) {
+	token, err := c.getToken(ctx)
 	if err != nil {
 		return nil, err
 	}
-	u, err := c.client.FindById(token, userId)
-	if err == ErrUnauthorized {
+	u, err := c.client.FindByIdContext(ctx, token, userId)
+	if errors.Is(err, ErrUnauthorized) {
 		c.tokenHolder.Invalidate()
 	}
 	return u, err
 }
 
 func (c *ClientWithTokenHolder) FindAll() ([]*User, error) {
-	token, err := c.tokenHolder.GetToken(c.client)
+	return c.FindAllContext(context.Background())
+}
+
+func (c *ClientWithTokenHolder) FindAllContext(ctx context.Context) ([]*User, error) {
+	token, err := c.getToken(ctx)
 	if err != nil {
 		return nil, err
 	}
-	users, err := c.client.FindAll(token)
-	if err == ErrUnauthorized {
+	users, err := c.client.FindAllContext(ctx, token)
+	if errors.Is(err, ErrUnauthorized) {
 		c.tokenHolder.Invalidate()
 	}
 	return users, err
 }
 
 func (c *ClientWithTokenHolder) RevokedTokens() ([]RevokedToken, error) {
-	token, err := c.tokenHolder.GetToken(c.client)
+	return c.RevokedTokensContext(context.Background())
+}
+
+func (c *ClientWithTokenHolder) RevokedTokensContext(ctx context.Context) ([]RevokedToken, error) {
+	token, err := c.getToken(ctx)
 	if err != nil {
 		return nil, err
 	}
-	tokens, err := c.client.RevokedTokens(token)
-	if err == ErrUnauthorized {
+	tokens, err := c.client.RevokedTokensContext(ctx, token)
+	if errors.Is(err, ErrUnauthorized) {
 		c.tokenHolder.Invalidate()
 	}
 	return tokens, err
 }
+
+// getToken obtains the token within ctx if the holder supports it
+func (c *ClientWithTokenHolder) getToken(ctx context.Context) (string, error) {
+	if h, ok := c.tokenHolder.(ContextTokenHolder); ok {
+		return h.GetTokenContext(ctx, c.client)
+	}
+	return c.tokenHolder.GetToken(c.client)
+}
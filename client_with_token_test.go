@@ -1,6 +1,7 @@
 package userclient
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -169,6 +170,30 @@ func Test_ClientWithTokenHolder_Pass_Errors(t *testing.T) {
 	}
 }
 
+func Test_ClientWithTokenHolder_Context(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	c := &UserClientMock{
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			if ctx.Value(key{}) != "value" {
+				return nil, errors.New("wrong context")
+			}
+			return &User{}, nil
+		},
+	}
+	holder := &tokenHolderMock{
+		GetTokenFunc: func(_ Client) (string, error) {
+			return "token", nil
+		},
+	}
+	client := NewClientWithTokenHolder(c, holder)
+
+	if _, err := client.MeContext(ctx); err != nil {
+		t.Errorf("Shouldn't return error, got '%s'", err)
+	}
+}
+
 type tokenHolderMock struct {
 	GetTokenFunc   func(c Client) (string, error)
 	InvalidateFunc func()
@@ -0,0 +1,142 @@
+package userclient
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// defaultConditionalEntries is the size of the validator cache if not set
+const defaultConditionalEntries = 1000
+
+// WithConditionalRequests remembers ETag and Last-Modified of user reads
+// and sends conditional requests, 304 Not Modified reuses the previously decoded value.
+// Up to maxEntries resources are remembered, 1000 if it's not positive.
+func WithConditionalRequests(maxEntries int) Option {
+	return func(c *HttpClient) error {
+		if maxEntries <= 0 {
+			maxEntries = defaultConditionalEntries
+		}
+		c.conditionalCache = newConditionalCache(maxEntries)
+		return nil
+	}
+}
+
+type conditionalEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	value        interface{}
+}
+
+// conditionalCache is LRU cache of validators and decoded values
+type conditionalCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+func newConditionalCache(max int) *conditionalCache {
+	return &conditionalCache{
+		max:     max,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *conditionalCache) get(key string) (conditionalEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return conditionalEntry{}, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(conditionalEntry), true
+}
+
+func (c *conditionalCache) set(entry conditionalEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[entry.key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	if c.lru.Len() > c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(conditionalEntry).key)
+	}
+}
+
+// conditionalKey identifies the resource as seen by the token of the request,
+// the token is hashed so that it isn't kept in memory
+func conditionalKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(auth[:])
+}
+
+// isConditional reports whether 304 Not Modified is expected for the request
+func isConditional(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+// doConditionalRequest sends GET request with validators of the remembered value if conditional
+// requests are enabled. The value is cloned so that callers can't modify the remembered one.
+func doConditionalRequest[T any](c *HttpClient, req *http.Request, decode func(r io.Reader) (T, error), clone func(T) T) (T, error) {
+	var zero T
+	cache := c.conditionalCache
+	if cache == nil || req.Method != http.MethodGet {
+		resp, err := c.makeRequest(req)
+		if err != nil {
+			return zero, err
+		}
+		defer resp.Body.Close()
+		return decode(resp.Body)
+	}
+
+	key := conditionalKey(req)
+	entry, cached := cache.get(key)
+	if cached {
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := c.makeRequest(req)
+	if err != nil {
+		return zero, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		value, ok := entry.value.(T)
+		if !cached || !ok {
+			return zero, fmt.Errorf("%s %s: unexpected %d response", req.Method, req.URL.Path, resp.StatusCode)
+		}
+		return clone(value), nil
+	}
+
+	value, err := decode(resp.Body)
+	if err != nil {
+		return zero, err
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		cache.set(conditionalEntry{key: key, etag: etag, lastModified: lastModified, value: clone(value)})
+	}
+
+	return value, nil
+}
@@ -0,0 +1,92 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHttpClient_ConditionalRequests(t *testing.T) {
+	userRequests, notModified := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/users/1":
+				userRequests++
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				w.Write([]byte(`{"data": {"id": "1", "roles": ["Admin"]}}`))
+			case "/users/1/platforms":
+				if r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+				w.Write([]byte(`{"data": [{"name": "vn"}]}`))
+			}
+		}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, WithConditionalRequests(0))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	user, err := client.FindByIdContext(context.Background(), "token", "1")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	// changes of the returned user don't affect the remembered one
+	user.Roles[0] = "changed"
+	user.PlatformNames = nil
+
+	user, err = client.FindByIdContext(context.Background(), "token", "1")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if notModified != 1 {
+		t.Errorf("Wrong number of 304 responses %d", notModified)
+	}
+	if user.Id != "1" || user.Roles[0] != RoleAdmin || len(user.PlatformNames) != 1 || user.PlatformNames[0] != "vn" {
+		t.Errorf("Wrong user returned: %+v", user)
+	}
+
+	// validators are remembered per token
+	client.FindByIdContext(context.Background(), "other-token", "1")
+	if userRequests != 3 || notModified != 1 {
+		t.Errorf("Wrong requests: %d, 304 responses: %d", userRequests, notModified)
+	}
+}
+
+func TestHttpClient_NotModifiedWithoutConditionalRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL)
+	if _, err := client.FindByIdContext(context.Background(), "token", "1"); err == nil {
+		t.Error("error should be returned for unexpected 304 response")
+	}
+}
+
+func TestConditionalCache_Eviction(t *testing.T) {
+	cache := newConditionalCache(2)
+	cache.set(conditionalEntry{key: "a"})
+	cache.set(conditionalEntry{key: "b"})
+	cache.get("a")
+	cache.set(conditionalEntry{key: "c"})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	if _, ok := cache.get("a"); !ok {
+		t.Error("recently used entry should be kept")
+	}
+}
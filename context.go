@@ -7,6 +7,7 @@ type ctxKey int
 const (
 	userCtxKey ctxKey = iota
 	userTokenCtxKey
+	requestIDCtxKey
 )
 
 // GetCurrentUserFromContext return user from context or nil
@@ -36,3 +37,17 @@ func GetTokenFromContext(ctx context.Context) string {
 func ContextWithToken(ctx context.Context, token string) context.Context {
 	return context.WithValue(ctx, userTokenCtxKey, token)
 }
+
+// GetRequestIDFromContext return request id from context, empty string if it not exists
+func GetRequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDCtxKey).(string); ok {
+		return requestID
+	}
+
+	return ""
+}
+
+// ContextWithRequestID add request id to context, it's propagated by RequestIDTransport
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey, requestID)
+}
@@ -3,7 +3,7 @@ set -e
 
 cd /go/src/user-service-go-client
 
-go tool vet *.go
+go vet .
 go test -redisAddr redis:6379 --cover
 FILES=$(gofmt -l *.go)
 echo $FILES
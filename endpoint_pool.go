@@ -0,0 +1,240 @@
+package userclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointStrategy defines the order endpoints of EndpointPool are tried in
+type EndpointStrategy int
+
+const (
+	// StrategyRoundRobin spreads requests over all healthy endpoints
+	StrategyRoundRobin EndpointStrategy = iota
+	// StrategyPriority sends requests to the first healthy endpoint in order they are given
+	StrategyPriority
+)
+
+type EndpointPoolConfig struct {
+	Strategy EndpointStrategy
+	// CoolDown is the time an endpoint is ejected for after a connection error or 5xx response,
+	// 30 seconds if not set
+	CoolDown time.Duration
+	// MaxAttempts limits the number of endpoints tried by an idempotent request,
+	// all endpoints are tried if it's zero
+	MaxAttempts int
+}
+
+const defaultEndpointCoolDown = 30 * time.Second
+
+type endpoint struct {
+	url          *url.URL
+	ejectedUntil time.Time
+}
+
+// EndpointPool is http.RoundTripper spreading requests over endpoints of user service.
+// Requests have to be built against the first endpoint, they are rewritten to the chosen one.
+// Endpoints failing with connection errors or 5xx responses are ejected for the cool-down
+// and idempotent requests are repeated on the next endpoint.
+type EndpointPool struct {
+	config    EndpointPoolConfig
+	transport http.RoundTripper
+	now       func() time.Time
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	next      uint32
+}
+
+// NewEndpointPool returns pool sending requests with http.DefaultTransport,
+// see Wrap to use another transport
+func NewEndpointPool(endpoints []string, config EndpointPoolConfig) (*EndpointPool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = defaultEndpointCoolDown
+	}
+
+	p := &EndpointPool{
+		config:    config,
+		transport: http.DefaultTransport,
+		now:       time.Now,
+	}
+	for _, e := range endpoints {
+		u, err := url.Parse(strings.TrimSuffix(e, "/"))
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("endpoint %q should be absolute", e)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u})
+	}
+
+	return p, nil
+}
+
+// Wrap sets transport requests are sent with, it's TransportMiddleware
+func (p *EndpointPool) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	p.transport = next
+	return p
+}
+
+// HealthyEndpoints returns endpoints which aren't ejected
+func (p *EndpointPool) HealthyEndpoints() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var healthy []string
+	for _, e := range p.endpoints {
+		if !now.Before(e.ejectedUntil) {
+			healthy = append(healthy, e.url.String())
+		}
+	}
+	return healthy
+}
+
+func (p *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, ok := p.relativePath(req.URL)
+	if !ok {
+		return p.transport.RoundTrip(req)
+	}
+
+	candidates := p.candidates()
+	maxAttempts := len(candidates)
+	if p.config.MaxAttempts > 0 && p.config.MaxAttempts < maxAttempts {
+		maxAttempts = p.config.MaxAttempts
+	}
+	if !isIdempotent(req.Method) {
+		maxAttempts = 1
+	}
+
+	var resp *http.Response
+	var err error
+	for i, e := range candidates[:maxAttempts] {
+		next := req
+		if i > 0 {
+			rewound, rewindErr := rewindRequest(req)
+			if rewindErr != nil {
+				return resp, err
+			}
+			next = rewound
+		}
+		next = next.Clone(next.Context())
+		next.URL = e.rewrite(req.URL, path)
+		next.Host = ""
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		resp, err = p.transport.RoundTrip(next)
+		if !p.failed(req, resp, err) {
+			p.restore(e)
+			return resp, err
+		}
+		p.eject(e)
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+
+	return resp, err
+}
+
+// relativePath returns escaped path of the request relative to the first endpoint
+func (p *EndpointPool) relativePath(u *url.URL) (string, bool) {
+	base := p.endpoints[0].url
+	if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.EscapedPath(), base.EscapedPath()) {
+		return "", false
+	}
+	return strings.TrimPrefix(u.EscapedPath(), base.EscapedPath()), true
+}
+
+func (e *endpoint) rewrite(u *url.URL, path string) *url.URL {
+	rewritten := *u
+	rewritten.Scheme = e.url.Scheme
+	rewritten.Host = e.url.Host
+	rewritten.RawPath = e.url.EscapedPath() + path
+	// the path was escaped by url.URL, it can be unescaped
+	rewritten.Path, _ = url.PathUnescape(rewritten.RawPath)
+	return &rewritten
+}
+
+// candidates returns healthy endpoints in order of the strategy followed by ejected ones
+func (p *EndpointPool) candidates() []*endpoint {
+	start := 0
+	if p.config.Strategy == StrategyRoundRobin {
+		start = int((atomic.AddUint32(&p.next, 1) - 1) % uint32(len(p.endpoints)))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	healthy := make([]*endpoint, 0, len(p.endpoints))
+	var ejected []*endpoint
+	for i := range p.endpoints {
+		e := p.endpoints[(start+i)%len(p.endpoints)]
+		if now.Before(e.ejectedUntil) {
+			ejected = append(ejected, e)
+		} else {
+			healthy = append(healthy, e)
+		}
+	}
+
+	return append(healthy, ejected...)
+}
+
+// failed reports whether the endpoint should be ejected,
+// canceled requests don't tell anything about the endpoint
+func (p *EndpointPool) failed(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	for _, code := range serviceUnavailableCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *EndpointPool) eject(e *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.ejectedUntil = p.now().Add(p.config.CoolDown)
+}
+
+func (p *EndpointPool) restore(e *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.ejectedUntil = time.Time{}
+}
+
+// WithEndpoints spreads requests over the base URL of the client and the endpoints,
+// the base URL is the first endpoint of the pool, i.e. the primary one for StrategyPriority.
+// It wraps the transport configured so far.
+func WithEndpoints(config EndpointPoolConfig, endpoints ...string) Option {
+	return func(c *HttpClient) error {
+		rb, ok := c.requestBuilder.(*HttpRequestBuilderImpl)
+		if !ok {
+			return errors.New("endpoints require HttpRequestBuilderImpl")
+		}
+		pool, err := NewEndpointPool(append([]string{rb.BaseURL}, endpoints...), config)
+		if err != nil {
+			return err
+		}
+		return WithTransportMiddleware(pool.Wrap)(c)
+	}
+}
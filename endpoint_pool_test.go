@@ -0,0 +1,117 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEndpointPool_RoundRobin(t *testing.T) {
+	var calls [2]int
+	servers := make([]*httptest.Server, 2)
+	for i := range servers {
+		i := i
+		servers[i] = httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				calls[i]++
+				if r.URL.EscapedPath() != "/api/users/1/roles/a%2Fb" {
+					t.Errorf("Wrong path %s", r.URL.EscapedPath())
+				}
+			}))
+		defer servers[i].Close()
+	}
+
+	client, err := NewClient(servers[0].URL+"/api", WithEndpoints(EndpointPoolConfig{}, servers[1].URL+"/api"))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := client.GrantRoles(context.Background(), "token", "1", "a/b"); err != nil {
+			t.Errorf("error '%s' returned", err)
+		}
+	}
+
+	if calls[0] != 2 || calls[1] != 2 {
+		t.Errorf("Wrong distribution of requests %v", calls)
+	}
+}
+
+func TestEndpointPool_Failover(t *testing.T) {
+	primaryCalls, secondaryCalls := 0, 0
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			primaryCalls++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			secondaryCalls++
+		}))
+	defer secondary.Close()
+
+	pool, err := NewEndpointPool([]string{primary.URL, secondary.URL}, EndpointPoolConfig{
+		Strategy: StrategyPriority,
+		CoolDown: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+	client := New(&HttpRequestBuilderImpl{BaseURL: primary.URL}, &http.Client{Transport: pool})
+
+	if err := client.DeleteUser(context.Background(), "token", "1"); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if primaryCalls != 1 || secondaryCalls != 1 {
+		t.Errorf("Wrong calls: primary %d, secondary %d", primaryCalls, secondaryCalls)
+	}
+
+	// the primary is ejected
+	client.DeleteUser(context.Background(), "token", "1")
+	if primaryCalls != 1 || secondaryCalls != 2 {
+		t.Errorf("Wrong calls: primary %d, secondary %d", primaryCalls, secondaryCalls)
+	}
+	if healthy := pool.HealthyEndpoints(); len(healthy) != 1 || healthy[0] != secondary.URL {
+		t.Errorf("Wrong healthy endpoints %v", healthy)
+	}
+
+	// POST isn't repeated on the next endpoint
+	now = now.Add(time.Minute)
+	if err := client.DeactivateUser(context.Background(), "token", "1"); err == nil {
+		t.Error("error should be returned")
+	}
+	if primaryCalls != 2 || secondaryCalls != 2 {
+		t.Errorf("Wrong calls: primary %d, secondary %d", primaryCalls, secondaryCalls)
+	}
+}
+
+func TestEndpointPool_ConnectionError(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+	up := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": {"id": "1"}}`))
+		}))
+	defer up.Close()
+
+	client, err := NewClient(down.URL, WithEndpoints(EndpointPoolConfig{Strategy: StrategyPriority}, up.URL))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if _, err := client.FindByIdContext(context.Background(), "token", "1"); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+}
+
+func TestNewEndpointPool_Invalid(t *testing.T) {
+	if _, err := NewEndpointPool(nil, EndpointPoolConfig{}); err == nil {
+		t.Error("error should be returned for no endpoints")
+	}
+	if _, err := NewEndpointPool([]string{"localhost:8080"}, EndpointPoolConfig{}); err == nil {
+		t.Error("error should be returned for relative endpoint")
+	}
+}
@@ -1,8 +1,16 @@
 package userclient
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"sort"
+	"time"
 )
 
 var (
@@ -10,13 +18,252 @@ var (
 
 	ErrUnauthorized = errors.New("unauthorized")
 
+	ErrForbidden = errors.New("forbidden")
+
 	ErrNotFound = errors.New("not found user")
 
 	ErrMissingToken = errors.New("missing token in response")
+
+	ErrConflict = errors.New("conflict")
+
+	ErrValidation = errors.New("validation failed")
+
+	// ErrRateLimited is matched by RateLimitError returned by RateLimitedClient
+	ErrRateLimited = errors.New("client rate limit exceeded")
+
+	// ErrCircuitOpen is returned by CircuitBreakerClient without calling user service,
+	// it matches ErrServiceUnavailable but isn't retryable
+	ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrServiceUnavailable)
 )
 
+// AssignmentError is returned when a role or a platform assignment of a user fails
+type AssignmentError struct {
+	// Op is one of "grant role", "revoke role", "attach platform", "detach platform"
+	Op     string
+	UserId string
+	Name   string
+	Err    error
+}
+
+func (e *AssignmentError) Error() string {
+	return fmt.Sprintf("%s %s of user %s: %s", e.Op, e.Name, e.UserId, e.Err)
+}
+
+func (e *AssignmentError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned by RateLimitedClient failing fast without calling user service
+type RateLimitError struct {
+	Method string
+	// Concurrency is true if the call exceeded the number of calls in flight
+	Concurrency bool
+	// RetryAfter is the time until the next call is allowed by the rate limit,
+	// it's zero when the concurrency limit is exceeded
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Concurrency {
+		return fmt.Sprintf("%s: %s: too many calls in flight", e.Method, ErrRateLimited)
+	}
+	return fmt.Sprintf("%s: %s: retry after %s", e.Method, ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// PlatformsError is returned along with users when platforms of some of them can't be fetched,
+// PlatformNames of these users are nil
+type PlatformsError struct {
+	// Errors by user id
+	Errors map[string]error
+}
+
+func (e *PlatformsError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("fetch platforms of %d users failed, user %s: %s", len(ids), ids[0], e.Errors[ids[0]])
+}
+
+func (e *PlatformsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// maxErrorBodySize limits size of the response body kept in APIError
+const maxErrorBodySize = 64 << 10
+
+// APIError is returned when user service responds with unsuccessful status code.
+// It matches the sentinel error of the status code with errors.Is,
+// e.g. errors.Is(err, ErrUnauthorized)
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+
+	// Code and Message are provided by user service, they may be empty
+	Code    string
+	Message string
+
+	// Fields are validation errors of the request fields, they are usually
+	// provided with ErrValidation
+	Fields []ValidationError
+
+	// Body is the response body truncated to 64KB
+	Body []byte
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, e.err)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	for i, f := range e.Fields {
+		if i == 0 {
+			msg += ":"
+		} else {
+			msg += ","
+		}
+		msg += " " + f.String()
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Retryable reports whether the request may succeed if it's repeated
+func (e *APIError) Retryable() bool {
+	return e.err == ErrServiceUnavailable || e.StatusCode == http.StatusTooManyRequests
+}
+
+// newAPIError reads the response body, it doesn't close it
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		err:        parseToError(resp.StatusCode),
+	}
+	if req := resp.Request; req != nil {
+		e.Method = req.Method
+		e.Path = req.URL.Path
+	}
+
+	e.Body, _ = ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	e.Code, e.Message = parseErrorBody(e.Body)
+	e.Fields = parseFieldErrors(e.Body)
+
+	return e
+}
+
+// parseErrorBody extracts code and message from the error response of user service,
+// both {"error": {"code": "", "message": ""}} and {"code": "", "message": ""} are supported,
+// as well as {"error": "message"}
+func parseErrorBody(body []byte) (code, message string) {
+	type errorDetails struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	var response struct {
+		errorDetails
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", ""
+	}
+
+	var details errorDetails
+	if err := json.Unmarshal(response.Error, &details); err == nil && (details.Code != "" || details.Message != "") {
+		return details.Code, details.Message
+	}
+	if err := json.Unmarshal(response.Error, &message); err == nil && message != "" {
+		return response.Code, message
+	}
+
+	return response.Code, response.Message
+}
+
+// ValidationError describes why a field of the request was rejected by user service
+type ValidationError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) String() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Code)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors returns field errors of the APIError wrapped by err, nil if there are none
+func ValidationErrors(err error) []ValidationError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Fields
+	}
+	return nil
+}
+
+// parseFieldErrors extracts field errors from the error response of user service,
+// they are listed in "fields" of the error object or at the top level,
+// or in "errors" as a list or as messages by field name, e.g. {"errors": {"password": ["too short"]}}
+func parseFieldErrors(body []byte) []ValidationError {
+	var response struct {
+		Fields []ValidationError `json:"fields"`
+		Errors json.RawMessage   `json:"errors"`
+		Error  *json.RawMessage  `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+
+	if response.Error != nil {
+		var details struct {
+			Fields []ValidationError `json:"fields"`
+		}
+		if err := json.Unmarshal(*response.Error, &details); err == nil && len(details.Fields) > 0 {
+			return details.Fields
+		}
+	}
+	if len(response.Fields) > 0 {
+		return response.Fields
+	}
+
+	var list []ValidationError
+	if err := json.Unmarshal(response.Errors, &list); err == nil && len(list) > 0 {
+		return list
+	}
+	var messages map[string][]string
+	if err := json.Unmarshal(response.Errors, &messages); err != nil || len(messages) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(messages))
+	for name := range messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var fields []ValidationError
+	for _, name := range names {
+		for _, message := range messages[name] {
+			fields = append(fields, ValidationError{Field: name, Message: message})
+		}
+	}
+	return fields
+}
+
 var serviceUnavailableCodes = []int{
-	http.StatusForbidden,
 	http.StatusInternalServerError,
 	http.StatusBadGateway,
 	http.StatusServiceUnavailable,
@@ -28,10 +275,22 @@ func parseToError(statusCode int) error {
 		return ErrUnauthorized
 	}
 
+	if statusCode == http.StatusForbidden {
+		return ErrForbidden
+	}
+
 	if statusCode == http.StatusNotFound {
 		return ErrNotFound
 	}
 
+	if statusCode == http.StatusConflict {
+		return ErrConflict
+	}
+
+	if statusCode == http.StatusUnprocessableEntity {
+		return ErrValidation
+	}
+
 	for _, c := range serviceUnavailableCodes {
 		if statusCode == c {
 			return ErrServiceUnavailable
@@ -40,3 +299,24 @@ func parseToError(statusCode int) error {
 
 	return errors.New(http.StatusText(statusCode))
 }
+
+// IsRetryable reports whether the failed call may succeed if it's repeated:
+// service unavailability, rate limiting and network errors are retryable,
+// while client errors like ErrUnauthorized or ErrForbidden and canceled calls aren't
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	if errors.Is(err, ErrServiceUnavailable) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -1,7 +1,12 @@
 package userclient
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -12,8 +17,13 @@ func TestParse(t *testing.T) {
 	}{
 		{401, ErrUnauthorized},
 		{404, ErrNotFound},
-		{403, ErrServiceUnavailable},
+		{403, ErrForbidden},
 		{500, ErrServiceUnavailable},
+		{502, ErrServiceUnavailable},
+		{503, ErrServiceUnavailable},
+		{504, ErrServiceUnavailable},
+		{409, ErrConflict},
+		{422, ErrValidation},
 	}
 
 	for _, c := range cases {
@@ -39,3 +49,113 @@ func TestParseWithUnknownStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestParseErrorBody(t *testing.T) {
+	cases := []struct {
+		Body    string
+		Code    string
+		Message string
+	}{
+		{`{"error": {"code": "c", "message": "m"}}`, "c", "m"},
+		{`{"code": "c", "message": "m"}`, "c", "m"},
+		{`{"error": "m"}`, "", "m"},
+		{`not a json`, "", ""},
+		{``, "", ""},
+	}
+
+	for _, c := range cases {
+		code, message := parseErrorBody([]byte(c.Body))
+		if code != c.Code || message != c.Message {
+			t.Errorf("Expect %v, %v - Got %v, %v", c.Code, c.Message, code, message)
+		}
+	}
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	cases := []struct {
+		Body   string
+		Expect []ValidationError
+	}{
+		{`{"error": {"code": "c", "fields": [{"field": "password", "code": "too_short", "message": "m"}]}}`, []ValidationError{{"password", "too_short", "m"}}},
+		{`{"message": "m", "fields": [{"field": "email", "code": "invalid"}]}`, []ValidationError{{Field: "email", Code: "invalid"}}},
+		{`{"errors": [{"field": "token", "message": "expired"}]}`, []ValidationError{{Field: "token", Message: "expired"}}},
+		{`{"errors": {"username": ["required"], "email": ["required", "invalid"]}}`, []ValidationError{
+			{Field: "email", Message: "required"},
+			{Field: "email", Message: "invalid"},
+			{Field: "username", Message: "required"},
+		}},
+		{`{"error": "m"}`, nil},
+		{`not a json`, nil},
+	}
+
+	for _, c := range cases {
+		fields := parseFieldErrors([]byte(c.Body))
+		if !reflect.DeepEqual(fields, c.Expect) {
+			t.Errorf("%s: Expect %v - Got %v", c.Body, c.Expect, fields)
+		}
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	var err error = &APIError{StatusCode: 401, err: ErrUnauthorized}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Error("APIError should match ErrUnauthorized")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("APIError shouldn't match ErrNotFound")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		Err    error
+		Expect bool
+	}{
+		{nil, false},
+		{ErrServiceUnavailable, true},
+		{ErrUnauthorized, false},
+		{ErrForbidden, false},
+		{ErrNotFound, false},
+		{errors.New("unknown"), false},
+		{context.Canceled, false},
+		{ErrCircuitOpen, false},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{&url.Error{Op: "Get", URL: "/users/me", Err: context.Canceled}, false},
+	}
+
+	for _, c := range cases {
+		result := IsRetryable(c.Err)
+		if result != c.Expect {
+			t.Errorf("%v: Expect %v - Got %v", c.Err, c.Expect, result)
+		}
+	}
+}
+
+func TestAPIErrorRetryable(t *testing.T) {
+	cases := []struct {
+		StatusCode int
+		Expect     bool
+	}{
+		{400, false},
+		{401, false},
+		{403, false},
+		{404, false},
+		{409, false},
+		{422, false},
+		{429, true},
+		{500, true},
+		{502, true},
+		{503, true},
+		{504, true},
+	}
+
+	for _, c := range cases {
+		err := &APIError{StatusCode: c.StatusCode, err: parseToError(c.StatusCode)}
+		if err.Retryable() != c.Expect {
+			t.Errorf("%d: Expect %v - Got %v", c.StatusCode, c.Expect, err.Retryable())
+		}
+		if IsRetryable(err) != c.Expect {
+			t.Errorf("%d: Expect IsRetryable %v - Got %v", c.StatusCode, c.Expect, IsRetryable(err))
+		}
+	}
+}
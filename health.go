@@ -0,0 +1,139 @@
+package userclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthChecker reports whether a dependency is healthy
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts function to HealthChecker, e.g. HealthCheckerFunc(client.Ping)
+type HealthCheckerFunc func(ctx context.Context) error
+
+func (f HealthCheckerFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Health statuses
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// DefaultHealthCheckTimeout limits every check run by HealthHandler
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthReport is the aggregated status of health checks
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+type HealthCheckResult struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// CheckHealth runs the checks concurrently, the report is healthy if all checks pass
+func CheckHealth(ctx context.Context, checks map[string]HealthChecker) HealthReport {
+	report := HealthReport{
+		Status: HealthStatusOK,
+		Checks: make(map[string]HealthCheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthChecker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check.CheckHealth(ctx)
+			result := HealthCheckResult{Status: HealthStatusOK, Duration: time.Since(start)}
+			if err != nil {
+				result.Status = HealthStatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = HealthStatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// HealthHandler responds with JSON HealthReport of the checks,
+// the status code is 200 if all checks pass and 503 otherwise.
+// Every check is limited by DefaultHealthCheckTimeout.
+func HealthHandler(checks map[string]HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), DefaultHealthCheckTimeout)
+		defer cancel()
+
+		report := CheckHealth(ctx, checks)
+		code := http.StatusOK
+		if report.Status != HealthStatusOK {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// CheckHealth fails while the breaker is open, half-open state is considered healthy
+func (b *CircuitBreakerClient) CheckHealth(ctx context.Context) error {
+	if b.State() == StateOpen {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+const healthCacheKey = "user-middleware/health"
+
+// CacheHealthChecker checks the cache by writing and reading a probe value
+func CacheHealthChecker(cache Cache) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		if err := cache.Set(healthCacheKey, time.Now().Unix()); err != nil {
+			return fmt.Errorf("cache write failed: %w", err)
+		}
+		var probe int64
+		if err := cache.Get(healthCacheKey, &probe); err != nil {
+			return fmt.Errorf("cache read failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// PubSubHealthChecker fails if the subscription had no activity for maxIdle,
+// pubsub returned by SubscribeRedis checks its connection every minute.
+func PubSubHealthChecker(p PubSub, maxIdle time.Duration) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		activity, ok := p.(interface{ LastActivity() time.Time })
+		if !ok {
+			return errors.New("pubsub doesn't report its activity")
+		}
+		if idle := time.Since(activity.LastActivity()); idle > maxIdle {
+			return fmt.Errorf("pubsub has been inactive for %s", idle.Round(time.Second))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package userclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_Ping(t *testing.T) {
+	healthy := true
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != healthPath {
+				t.Errorf("Wrong path %s", r.URL.Path)
+			}
+			if r.Header.Get("Authorization") != "" {
+				t.Error("health check should not be authorized")
+			}
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+
+	healthy = false
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+}
+
+type activePubSub struct {
+	lastActivity time.Time
+}
+
+func (p *activePubSub) ReceiveMsg() (Message, error) {
+	return Message{}, nil
+}
+
+func (p *activePubSub) LastActivity() time.Time {
+	return p.lastActivity
+}
+
+func TestHealthHandler(t *testing.T) {
+	breaker := NewCircuitBreakerClient(&UserClientMock{}, DefaultCircuitBreakerConfig)
+	pubsub := &activePubSub{lastActivity: time.Now()}
+	handler := HealthHandler(map[string]HealthChecker{
+		"user_service": HealthCheckerFunc(func(ctx context.Context) error { return nil }),
+		"circuit":      breaker,
+		"cache":        CacheHealthChecker(newCachedMock()),
+		"pubsub":       PubSubHealthChecker(pubsub, time.Minute),
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Wrong status code %d: %s", w.Code, w.Body)
+	}
+
+	pubsub.lastActivity = time.Now().Add(-time.Hour)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wrong status code %d", w.Code)
+	}
+
+	var report HealthReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if report.Status != HealthStatusUnavailable || len(report.Checks) != 4 {
+		t.Errorf("Wrong report %+v", report)
+	}
+	if result := report.Checks["pubsub"]; result.Status != HealthStatusUnavailable || result.Error == "" {
+		t.Errorf("Wrong pubsub result %+v", result)
+	}
+	if result := report.Checks["cache"]; result.Status != HealthStatusOK {
+		t.Errorf("Wrong cache result %+v", result)
+	}
+}
+
+func TestCircuitBreakerClient_CheckHealth(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	breaker := NewCircuitBreakerClient(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return nil, ErrServiceUnavailable
+		},
+	}, CircuitBreakerConfig{MinRequests: 1, FailureRatio: 0.5, CoolDown: time.Minute})
+	breaker.now = clock.Now
+
+	breaker.Me("token")
+	if err := breaker.CheckHealth(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+
+	clock.Add(time.Minute)
+	if err := breaker.CheckHealth(context.Background()); err != nil {
+		t.Errorf("half-open breaker should be healthy, error '%s' returned", err)
+	}
+}
@@ -0,0 +1,175 @@
+package userclient
+
+import (
+	"context"
+	"time"
+)
+
+// HedgeMetrics receives outcomes of hedged calls,
+// it's reported only when the hedged request has been sent
+type HedgeMetrics interface {
+	ObserveHedge(method string, hedgeWon bool)
+}
+
+type HedgeConfig struct {
+	// Delay before the hedged request is sent, e.g. p95 latency of user service
+	Delay time.Duration
+	// Methods to hedge, all read-only methods if empty.
+	// Methods changing users are never hedged.
+	Methods []string
+}
+
+// readOnlyMethods may be sent twice without side effects
+var readOnlyMethods = []string{
+	MethodMe,
+	MethodFindById,
+	MethodFindByIds,
+	MethodFindAll,
+	MethodListUsers,
+	MethodSearchUsers,
+	MethodRevokedTokens,
+}
+
+// HedgedClient sends a second request if the first one hasn't answered within the delay
+// and returns whichever answers first, the other one is canceled.
+// A retryable failure of the first answer is replaced by the other answer if it succeeds.
+type HedgedClient struct {
+	*interceptedClient
+
+	config  HedgeConfig
+	methods map[string]bool
+	metrics HedgeMetrics
+}
+
+func NewHedgedClient(c ContextClient, config HedgeConfig) *HedgedClient {
+	methods := config.Methods
+	if len(methods) == 0 {
+		methods = readOnlyMethods
+	}
+	client := &HedgedClient{
+		config:  config,
+		methods: make(map[string]bool, len(methods)),
+	}
+	for _, method := range methods {
+		for _, readOnly := range readOnlyMethods {
+			if method == readOnly {
+				client.methods[method] = true
+			}
+		}
+	}
+	client.interceptedClient = &interceptedClient{
+		client: c,
+		intercept: func(ctx context.Context, method string, call func(ctx context.Context) error) error {
+			return call(ctx)
+		},
+	}
+	return client
+}
+
+// SetMetrics enables reporting of hedging outcomes,
+// PrometheusMetrics implements HedgeMetrics
+func (c *HedgedClient) SetMetrics(m HedgeMetrics) {
+	c.metrics = m
+}
+
+func (c *HedgedClient) Me(token string) (*User, error) {
+	return c.MeContext(context.Background(), token)
+}
+
+func (c *HedgedClient) MeContext(ctx context.Context, token string) (*User, error) {
+	return hedge(c, ctx, MethodMe, func(ctx context.Context) (*User, error) {
+		return c.client.MeContext(ctx, token)
+	})
+}
+
+func (c *HedgedClient) FindById(token, userId string) (*User, error) {
+	return c.FindByIdContext(context.Background(), token, userId)
+}
+
+func (c *HedgedClient) FindByIdContext(ctx context.Context, token, userId string) (*User, error) {
+	return hedge(c, ctx, MethodFindById, func(ctx context.Context) (*User, error) {
+		return c.client.FindByIdContext(ctx, token, userId)
+	})
+}
+
+func (c *HedgedClient) FindByIds(ctx context.Context, token string, userIds []string) (map[string]*User, error) {
+	return hedge(c, ctx, MethodFindByIds, func(ctx context.Context) (map[string]*User, error) {
+		return c.client.FindByIds(ctx, token, userIds)
+	})
+}
+
+func (c *HedgedClient) FindAll(token string) ([]*User, error) {
+	return c.FindAllContext(context.Background(), token)
+}
+
+func (c *HedgedClient) FindAllContext(ctx context.Context, token string) ([]*User, error) {
+	return hedge(c, ctx, MethodFindAll, func(ctx context.Context) ([]*User, error) {
+		return c.client.FindAllContext(ctx, token)
+	})
+}
+
+func (c *HedgedClient) ListUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+	return hedge(c, ctx, MethodListUsers, func(ctx context.Context) (*UserPage, error) {
+		return c.client.ListUsers(ctx, token, params)
+	})
+}
+
+func (c *HedgedClient) SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error) {
+	return hedge(c, ctx, MethodSearchUsers, func(ctx context.Context) (*UserPage, error) {
+		return c.client.SearchUsers(ctx, token, filter, params)
+	})
+}
+
+func (c *HedgedClient) RevokedTokens(token string) ([]RevokedToken, error) {
+	return c.RevokedTokensContext(context.Background(), token)
+}
+
+func (c *HedgedClient) RevokedTokensContext(ctx context.Context, token string) ([]RevokedToken, error) {
+	return hedge(c, ctx, MethodRevokedTokens, func(ctx context.Context) ([]RevokedToken, error) {
+		return c.client.RevokedTokensContext(ctx, token)
+	})
+}
+
+type hedgeResult[T any] struct {
+	value T
+	err   error
+	hedge bool
+}
+
+func hedge[T any](c *HedgedClient, ctx context.Context, method string, call func(ctx context.Context) (T, error)) (T, error) {
+	if !c.methods[method] {
+		return call(ctx)
+	}
+
+	// the loser is canceled on return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult[T], 2)
+	run := func(hedge bool) {
+		value, err := call(ctx)
+		results <- hedgeResult[T]{value, err, hedge}
+	}
+	go run(false)
+
+	timer := time.NewTimer(c.config.Delay)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-timer.C:
+	}
+
+	go run(true)
+	r := <-results
+	if IsRetryable(r.err) {
+		if other := <-results; other.err == nil {
+			r = other
+		}
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveHedge(method, r.hedge)
+	}
+
+	return r.value, r.err
+}
@@ -0,0 +1,105 @@
+package userclient
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type hedgeMetricsMock struct {
+	mu   sync.Mutex
+	wins map[bool]int
+}
+
+func (m *hedgeMetricsMock) ObserveHedge(method string, hedgeWon bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wins[hedgeWon]++
+}
+
+func TestHedgedClient_HedgeWins(t *testing.T) {
+	var calls int32
+	canceled := make(chan struct{})
+	client := NewHedgedClient(&UserClientMock{
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				close(canceled)
+				return nil, ctx.Err()
+			}
+			return &User{Id: "hedge"}, nil
+		},
+	}, HedgeConfig{Delay: 10 * time.Millisecond})
+	metrics := &hedgeMetricsMock{wins: map[bool]int{}}
+	client.SetMetrics(metrics)
+
+	user, err := client.Me("token")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if user.Id != "hedge" {
+		t.Errorf("Wrong user %+v", user)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("slow request should be canceled")
+	}
+	if metrics.wins[true] != 1 {
+		t.Errorf("Wrong hedge metrics %v", metrics.wins)
+	}
+}
+
+func TestHedgedClient_FastPrimary(t *testing.T) {
+	var calls int32
+	client := NewHedgedClient(&UserClientMock{
+		FindByIdMock: func(token, userId string) (*User, error) {
+			atomic.AddInt32(&calls, 1)
+			return &User{Id: userId}, nil
+		},
+	}, HedgeConfig{Delay: time.Second})
+
+	if _, err := client.FindById("token", "1"); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if calls != 1 {
+		t.Errorf("Wrong number of calls %d", calls)
+	}
+}
+
+func TestHedgedClient_RetryableFailure(t *testing.T) {
+	var calls int32
+	client := NewHedgedClient(&UserClientMock{
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(20 * time.Millisecond)
+				return nil, ErrServiceUnavailable
+			}
+			time.Sleep(40 * time.Millisecond)
+			return &User{Id: "1"}, nil
+		},
+	}, HedgeConfig{Delay: 10 * time.Millisecond})
+
+	if _, err := client.Me("token"); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+}
+
+func TestHedgedClient_MutationsAreNotHedged(t *testing.T) {
+	var calls int32
+	client := NewHedgedClient(&UserClientMock{
+		DeleteUserMock: func(ctx context.Context, token, userId string) error {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		},
+	}, HedgeConfig{Delay: time.Millisecond, Methods: []string{MethodDeleteUser}})
+
+	client.DeleteUser(context.Background(), "token", "1")
+	if calls != 1 {
+		t.Errorf("Wrong number of calls %d", calls)
+	}
+}
@@ -0,0 +1,75 @@
+package userclient
+
+import "net/http"
+
+// BeforeRequestHook is called before every HTTP attempt,
+// returned error aborts the attempt
+type BeforeRequestHook func(req *http.Request) error
+
+// AfterResponseHook is called after every HTTP attempt which got a response,
+// the response body must not be read
+type AfterResponseHook func(req *http.Request, resp *http.Response)
+
+// ErrorHook is called after every failed HTTP attempt,
+// including attempts failed with unsuccessful status code
+type ErrorHook func(req *http.Request, err error)
+
+// OnBeforeRequest adds hooks called before every HTTP attempt in order they are added
+func (c *HttpClient) OnBeforeRequest(hooks ...BeforeRequestHook) {
+	c.beforeRequestHooks = append(c.beforeRequestHooks, hooks...)
+}
+
+// OnAfterResponse adds hooks called after every HTTP attempt which got a response
+func (c *HttpClient) OnAfterResponse(hooks ...AfterResponseHook) {
+	c.afterResponseHooks = append(c.afterResponseHooks, hooks...)
+}
+
+// OnError adds hooks called after every failed HTTP attempt
+func (c *HttpClient) OnError(hooks ...ErrorHook) {
+	c.errorHooks = append(c.errorHooks, hooks...)
+}
+
+// WithBeforeRequest adds hooks called before every HTTP attempt
+func WithBeforeRequest(hooks ...BeforeRequestHook) Option {
+	return func(c *HttpClient) error {
+		c.OnBeforeRequest(hooks...)
+		return nil
+	}
+}
+
+// WithAfterResponse adds hooks called after every HTTP attempt which got a response
+func WithAfterResponse(hooks ...AfterResponseHook) Option {
+	return func(c *HttpClient) error {
+		c.OnAfterResponse(hooks...)
+		return nil
+	}
+}
+
+// WithOnError adds hooks called after every failed HTTP attempt
+func WithOnError(hooks ...ErrorHook) Option {
+	return func(c *HttpClient) error {
+		c.OnError(hooks...)
+		return nil
+	}
+}
+
+func (c *HttpClient) runBeforeRequestHooks(req *http.Request) error {
+	for _, hook := range c.beforeRequestHooks {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *HttpClient) runAfterResponseHooks(req *http.Request, resp *http.Response) {
+	for _, hook := range c.afterResponseHooks {
+		hook(req, resp)
+	}
+}
+
+func (c *HttpClient) runErrorHooks(req *http.Request, err error) {
+	for _, hook := range c.errorHooks {
+		hook(req, err)
+	}
+}
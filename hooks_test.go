@@ -0,0 +1,68 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHttpClient_Hooks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Hook") != "1" {
+				t.Errorf("Wrong hook header %q", r.Header.Get("X-Hook"))
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	defer ts.Close()
+
+	var responses, failures int
+	client, err := NewClient(ts.URL,
+		WithBeforeRequest(func(req *http.Request) error {
+			req.Header.Set("X-Hook", "1")
+			return nil
+		}),
+		WithAfterResponse(func(req *http.Request, resp *http.Response) {
+			responses++
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("Wrong status code %d", resp.StatusCode)
+			}
+		}),
+		WithOnError(func(req *http.Request, err error) {
+			failures++
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("Wrong error '%s'", err)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	if _, err := client.FindByIdContext(context.Background(), "token", "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+	if responses != 1 || failures != 1 {
+		t.Errorf("Wrong hook calls: %d responses, %d failures", responses, failures)
+	}
+}
+
+func TestHttpClient_BeforeRequestHookError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not be sent")
+		}))
+	defer ts.Close()
+
+	hookErr := errors.New("hook failed")
+	client, _ := NewClient(ts.URL)
+	client.OnBeforeRequest(func(req *http.Request) error {
+		return hookErr
+	})
+
+	if err := client.DeleteUser(context.Background(), "token", "1"); !errors.Is(err, hookErr) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+}
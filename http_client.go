@@ -1,22 +1,60 @@
 package userclient
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type HttpClient struct {
 	requestBuilder HttpRequestBuilder
 	requestClient  *http.Client
+	retryPolicy    RetryPolicy
+	headers        http.Header
+	logger         Logger
+	metricsHook    MetricsHook
+	tracingHook    TracingHook
+
+	beforeRequestHooks []BeforeRequestHook
+	afterResponseHooks []AfterResponseHook
+	errorHooks         []ErrorHook
+
+	// ownTransport is the transport configured by options, it's not shared
+	ownTransport *http.Transport
+
+	conditionalCache *conditionalCache
+
+	// embedPlatforms requests users with embedded platforms in a single round-trip
+	embedPlatforms bool
+	// strictPlatforms fails user reads if platforms can't be fetched
+	strictPlatforms bool
+	// enrichmentConcurrency enables fetching platforms of list results
+	enrichmentConcurrency int
 }
 
 const DEFAULT_TIME_OUT = 10
 
+// findByIdsChunkSize limits number of ids requested at once to keep URLs short
+const findByIdsChunkSize = 100
+
+// pageMeta is pagination metadata of list responses
+type pageMeta struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor"`
+}
+
 func NewDefault(baseURL string) *HttpClient {
 	return &HttpClient{
 		requestBuilder: &HttpRequestBuilderImpl{BaseURL: baseURL},
 		requestClient:  &http.Client{Timeout: time.Second * DEFAULT_TIME_OUT},
+		logger:         NopLogger,
 	}
 }
 
@@ -24,59 +62,96 @@ func New(rb HttpRequestBuilder, hc *http.Client) *HttpClient {
 	return &HttpClient{
 		requestBuilder: rb,
 		requestClient:  hc,
+		logger:         NopLogger,
 	}
 }
 
 func (c *HttpClient) Authenticate(username string, password string) (string, error) {
-	req, err := c.requestBuilder.BuildLoginRequest(username, password)
-	if err != nil {
-		return "", err
-	}
+	return c.AuthenticateContext(context.Background(), username, password)
+}
 
-	resp, err := c.makeRequest(req)
+func (c *HttpClient) AuthenticateContext(ctx context.Context, username string, password string) (string, error) {
+	login, err := c.Login(ctx, username, password)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
+	return login.AccessToken, nil
+}
 
-	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
+func (c *HttpClient) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	req, err := c.requestBuilder.BuildLoginRequest(ctx, username, password)
+	if err != nil {
+		return nil, err
 	}
 
-	if val, ok := data["token"]; ok {
-		return val.(string), nil
-	}
-	return "", ErrMissingToken
+	return c.doLoginRequest(req)
 }
 
-func (c *HttpClient) Me(token string) (*User, error) {
-	var response struct {
-		Data User
-	}
-
-	req, err := c.requestBuilder.BuildMeRequest(token)
+func (c *HttpClient) Refresh(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+	req, err := c.requestBuilder.BuildRefreshRequest(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
+	return c.doLoginRequest(req)
+}
+
+// doLoginRequest decodes {"token": "", "refreshToken": "", "expiresAt": ""},
+// "accessToken" may be used instead of "token" and "expiresIn" in seconds instead of "expiresAt"
+func (c *HttpClient) doLoginRequest(req *http.Request) (*LoginResponse, error) {
 	resp, err := c.makeRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	var data struct {
+		Token        string    `json:"token"`
+		AccessToken  string    `json:"accessToken"`
+		RefreshToken string    `json:"refreshToken"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+		ExpiresIn    int64     `json:"expiresIn"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		return nil, err
 	}
 
-	c.addPlatformsToUser(token, &response.Data)
+	login := &LoginResponse{
+		AccessToken:  data.Token,
+		RefreshToken: data.RefreshToken,
+		ExpiresAt:    data.ExpiresAt,
+	}
+	if login.AccessToken == "" {
+		login.AccessToken = data.AccessToken
+	}
+	if login.AccessToken == "" {
+		return nil, ErrMissingToken
+	}
+	if login.ExpiresAt.IsZero() && data.ExpiresIn > 0 {
+		login.ExpiresAt = time.Now().Add(time.Duration(data.ExpiresIn) * time.Second)
+	}
+	return login, nil
+}
 
-	return &response.Data, nil
+func (c *HttpClient) Me(token string) (*User, error) {
+	return c.MeContext(context.Background(), token)
+}
+
+func (c *HttpClient) MeContext(ctx context.Context, token string) (*User, error) {
+	req, err := c.requestBuilder.BuildMeRequest(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doUserWithPlatformsRequest(ctx, token, req)
 }
 
 func (c *HttpClient) Logout(token string) error {
-	req, err := c.requestBuilder.BuildLogoutRequest(token)
+	return c.LogoutContext(context.Background(), token)
+}
+
+func (c *HttpClient) LogoutContext(ctx context.Context, token string) error {
+	req, err := c.requestBuilder.BuildLogoutRequest(ctx, token)
 	if err != nil {
 		return err
 	}
@@ -89,54 +164,130 @@ func (c *HttpClient) Logout(token string) error {
 }
 
 func (c *HttpClient) FindById(token, userId string) (*User, error) {
-	var response struct {
-		Data User
-	}
+	return c.FindByIdContext(context.Background(), token, userId)
+}
 
-	req, err := c.requestBuilder.BuildGetRequest(token, userId)
+func (c *HttpClient) FindByIdContext(ctx context.Context, token, userId string) (*User, error) {
+	req, err := c.requestBuilder.BuildGetRequest(ctx, token, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.makeRequest(req)
-	if err != nil {
-		return nil, err
+	return c.doUserWithPlatformsRequest(ctx, token, req)
+}
+
+func (c *HttpClient) FindByIds(ctx context.Context, token string, userIds []string) (map[string]*User, error) {
+	users := make(map[string]*User, len(userIds))
+	ids := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		if _, ok := users[id]; !ok {
+			users[id] = nil
+			ids = append(ids, id)
+		}
 	}
-	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
+	for start := 0; start < len(ids); start += findByIdsChunkSize {
+		end := start + findByIdsChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		req, err := c.requestBuilder.BuildFindByIdsRequest(ctx, token, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		page, err := c.doListRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.Users {
+			if _, ok := users[u.Id]; ok {
+				users[u.Id] = u
+			}
+		}
 	}
 
-	c.addPlatformsToUser(token, &response.Data)
+	found := make([]*User, 0, len(users))
+	for _, u := range users {
+		if u != nil {
+			found = append(found, u)
+		}
+	}
+	if err := c.enrichPlatforms(ctx, token, found); err != nil {
+		return users, err
+	}
 
-	return &response.Data, nil
+	return users, nil
 }
 
 func (c *HttpClient) FindAll(token string) ([]*User, error) {
-	req, err := c.requestBuilder.BuildGetAllRequest(token)
+	return c.FindAllContext(context.Background(), token)
+}
+
+// FindAllContext returns all users, platforms are fetched once all pages are read
+func (c *HttpClient) FindAllContext(ctx context.Context, token string) ([]*User, error) {
+	users := make([]*User, 0)
+	it := newUserIterator(ctx, ListUsersParams{}, func(ctx context.Context, params ListUsersParams) (*UserPage, error) {
+		return c.listUsers(ctx, token, params)
+	})
+	for it.Next() {
+		users = append(users, it.User())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := c.enrichPlatforms(ctx, token, users); err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+func (c *HttpClient) ListUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+	page, err := c.listUsers(ctx, token, params)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.enrichPlatforms(ctx, token, page.Users); err != nil {
+		return page, err
+	}
 
-	resp, err := c.makeRequest(req)
+	return page, nil
+}
+
+func (c *HttpClient) listUsers(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+	req, err := c.requestBuilder.BuildListUsersRequest(ctx, token, params)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	usersResponse := struct {
-		Data []*User `json:"data"`
-	}{Data: make([]*User, 0)}
-	if err := json.NewDecoder(resp.Body).Decode(&usersResponse); err != nil {
+	return c.doListRequest(req)
+}
+
+func (c *HttpClient) SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*UserPage, error) {
+	req, err := c.requestBuilder.BuildSearchUsersRequest(ctx, token, filter, params)
+	if err != nil {
 		return nil, err
 	}
 
-	return usersResponse.Data, nil
+	page, err := c.doListRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.enrichPlatforms(ctx, token, page.Users); err != nil {
+		return page, err
+	}
+
+	return page, nil
 }
 
 func (c *HttpClient) RevokedTokens(token string) ([]RevokedToken, error) {
-	req, err := c.requestBuilder.BuildRevokedTokensRequest(token)
+	return c.RevokedTokensContext(context.Background(), token)
+}
+
+func (c *HttpClient) RevokedTokensContext(ctx context.Context, token string) ([]RevokedToken, error) {
+	req, err := c.requestBuilder.BuildRevokedTokensRequest(ctx, token)
 	if err != nil {
 		return nil, err
 	}
@@ -156,41 +307,403 @@ func (c *HttpClient) RevokedTokens(token string) ([]RevokedToken, error) {
 	return tokensResponse.Data, nil
 }
 
-func (c *HttpClient) makeRequest(req *http.Request) (*http.Response, error) {
-	resp, err := c.requestClient.Do(req)
+func (c *HttpClient) CreateUser(ctx context.Context, token string, input CreateUserInput) (*User, error) {
+	req, err := c.requestBuilder.BuildCreateUserRequest(ctx, token, input)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, parseToError(resp.StatusCode)
+
+	return c.doUserRequest(req)
+}
+
+func (c *HttpClient) UpdateUser(ctx context.Context, token, userId string, input UpdateUserInput) (*User, error) {
+	req, err := c.requestBuilder.BuildUpdateUserRequest(ctx, token, userId, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doUserRequest(req)
+}
+
+func (c *HttpClient) PatchUser(ctx context.Context, token, userId string, input PatchUserInput) (*User, error) {
+	req, err := c.requestBuilder.BuildPatchUserRequest(ctx, token, userId, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doUserRequest(req)
+}
+
+func (c *HttpClient) DeactivateUser(ctx context.Context, token, userId string) error {
+	req, err := c.requestBuilder.BuildDeactivateUserRequest(ctx, token, userId)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req)
+}
+
+func (c *HttpClient) DeleteUser(ctx context.Context, token, userId string) error {
+	req, err := c.requestBuilder.BuildDeleteUserRequest(ctx, token, userId)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req)
+}
+
+func (c *HttpClient) GrantRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.assign("grant role", userId, roles, isAlreadyAssigned, func(role string) (*http.Request, error) {
+		return c.requestBuilder.BuildGrantRoleRequest(ctx, token, userId, role)
+	})
+}
+
+func (c *HttpClient) RevokeRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.assign("revoke role", userId, roles, isNotAssigned, func(role string) (*http.Request, error) {
+		return c.requestBuilder.BuildRevokeRoleRequest(ctx, token, userId, role)
+	})
+}
+
+func (c *HttpClient) AttachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.assign("attach platform", userId, platformNames, isAlreadyAssigned, func(name string) (*http.Request, error) {
+		return c.requestBuilder.BuildAttachPlatformRequest(ctx, token, userId, name)
+	})
+}
+
+func (c *HttpClient) DetachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.assign("detach platform", userId, platformNames, isNotAssigned, func(name string) (*http.Request, error) {
+		return c.requestBuilder.BuildDetachPlatformRequest(ctx, token, userId, name)
+	})
+}
+
+// Error codes of user service meaning the assignment is already in the desired state
+const (
+	// assignmentExistsCode comes with 409 to assignment of a role or a platform the user has
+	assignmentExistsCode = "assignment_exists"
+	// assignmentNotFoundCode comes with 404 to removal of a role or a platform the user doesn't have
+	assignmentNotFoundCode = "assignment_not_found"
+)
+
+// isAlreadyAssigned reports whether the role or the platform is already assigned,
+// other conflicts aren't
+func isAlreadyAssigned(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict && apiErr.Code == assignmentExistsCode
+}
+
+// isNotAssigned reports whether the role or the platform isn't assigned,
+// 404 of unknown user or other resources isn't
+func isNotAssigned(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Code == assignmentNotFoundCode
+}
+
+// assign makes assignment request per name and stops on the first failure,
+// done reports whether the error means the assignment is already in the desired state
+func (c *HttpClient) assign(op, userId string, names []string, done func(err error) bool, build func(name string) (*http.Request, error)) error {
+	for _, name := range names {
+		req, err := build(name)
+		if err == nil {
+			err = c.doRequest(req)
+		}
+		if err != nil && !done(err) {
+			return &AssignmentError{Op: op, UserId: userId, Name: name, Err: err}
+		}
 	}
 
-	return resp, nil
+	return nil
 }
 
-func (c *HttpClient) addPlatformsToUser(token string, user *User) error {
-	req, err := c.requestBuilder.BuildPlatformsRequest(token, user.Id)
+// Ping checks that user service is reachable and healthy,
+// the retry policy of the client applies
+func (c *HttpClient) Ping(ctx context.Context) error {
+	req, err := c.requestBuilder.BuildHealthRequest(ctx)
 	if err != nil {
 		return err
 	}
+	return c.doRequest(req)
+}
+
+// doRequest makes request which response body isn't needed
+func (c *HttpClient) doRequest(req *http.Request) error {
 	resp, err := c.makeRequest(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+// ChangePassword changes password of the user the token belongs to
+func (c *HttpClient) ChangePassword(ctx context.Context, token string, input ChangePasswordInput) error {
+	req, err := c.requestBuilder.BuildChangePasswordRequest(ctx, token, input)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	return c.doRequest(req)
+}
+
+// RequestPasswordReset sends reset token to the user, it doesn't require authentication
+func (c *HttpClient) RequestPasswordReset(ctx context.Context, input PasswordResetInput) error {
+	req, err := c.requestBuilder.BuildPasswordResetRequest(ctx, input)
+	if err != nil {
+		return err
+	}
+	return c.doRequest(req)
+}
+
+// ConfirmPasswordReset sets new password using the reset token
+func (c *HttpClient) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	req, err := c.requestBuilder.BuildConfirmPasswordResetRequest(ctx, input)
+	if err != nil {
+		return err
+	}
+	return c.doRequest(req)
+}
+
+func (c *HttpClient) doUserRequest(req *http.Request) (*User, error) {
+	return doConditionalRequest(c, req, decodeUser, (*User).clone)
+}
+
+// doUserWithPlatformsRequest reads the user with its platforms,
+// they are embedded in the response or fetched with another request
+func (c *HttpClient) doUserWithPlatformsRequest(ctx context.Context, token string, req *http.Request) (*User, error) {
+	if c.embedPlatforms {
+		query := req.URL.Query()
+		query.Set("include", "platforms")
+		req.URL.RawQuery = query.Encode()
+	}
+
+	user, err := c.doUserRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	// user service not supporting include parameter doesn't embed platforms
+	if c.embedPlatforms && user.PlatformNames != nil {
+		return user, nil
+	}
+
+	if err := c.addPlatformsToUser(ctx, token, user); err != nil {
+		if c.strictPlatforms {
+			return nil, fmt.Errorf("fetch platforms of user %s: %w", user.Id, err)
+		}
+		c.logger.Warn("user platforms fetch failed", Fields{FieldUserId: user.Id, FieldError: err.Error()})
+	}
+
+	return user, nil
+}
+
+func (c *HttpClient) doListRequest(req *http.Request) (*UserPage, error) {
+	return doConditionalRequest(c, req, decodeUserPage, (*UserPage).clone)
+}
+
+// decodeUser supports platforms embedded both as names and as objects
+func decodeUser(r io.Reader) (*User, error) {
+	var response struct {
+		Data struct {
+			User
+			Platforms json.RawMessage `json:"platforms"`
+		}
+	}
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	user := &response.Data.User
+	if platforms := response.Data.Platforms; len(platforms) > 0 && string(platforms) != "null" {
+		if err := json.Unmarshal(platforms, &user.PlatformNames); err != nil {
+			var objects []Platform
+			if err := json.Unmarshal(platforms, &objects); err != nil {
+				return nil, err
+			}
+			user.PlatformNames = make([]string, len(objects))
+			for i, p := range objects {
+				user.PlatformNames[i] = p.Name
+			}
+		}
+	}
+
+	return user, nil
+}
 
+func decodeUserPage(r io.Reader) (*UserPage, error) {
+	usersResponse := struct {
+		Data []*User   `json:"data"`
+		Meta *pageMeta `json:"meta"`
+	}{Data: make([]*User, 0)}
+	if err := json.NewDecoder(r).Decode(&usersResponse); err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: usersResponse.Data}
+	if meta := usersResponse.Meta; meta != nil {
+		page.Page = meta.Page
+		page.PerPage = meta.PerPage
+		page.Total = meta.Total
+		page.NextCursor = meta.NextCursor
+	}
+
+	return page, nil
+}
+
+func decodePlatformNames(r io.Reader) ([]string, error) {
 	platforms := struct {
 		Data []Platform `json:"data"`
 	}{Data: make([]Platform, 0)}
-	if err := json.NewDecoder(resp.Body).Decode(&platforms); err != nil {
-		return err
+	if err := json.NewDecoder(r).Decode(&platforms); err != nil {
+		return nil, err
 	}
 
-	user.PlatformNames = make([]string, len(platforms.Data), len(platforms.Data))
+	names := make([]string, len(platforms.Data))
 	for i, p := range platforms.Data {
-		user.PlatformNames[i] = p.Name
+		names[i] = p.Name
+	}
+
+	return names, nil
+}
+
+// SetLogger sets logger of HTTP attempts, nil disables logging
+func (c *HttpClient) SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger
+	}
+	c.logger = l
+}
+
+// SetRetryPolicy enables retries of failed requests, nil disables them
+func (c *HttpClient) SetRetryPolicy(p RetryPolicy) {
+	c.retryPolicy = p
+}
+
+func (c *HttpClient) makeRequest(req *http.Request) (*http.Response, error) {
+	for key, values := range c.headers {
+		if _, ok := req.Header[key]; !ok {
+			// the request must not share values with the defaults
+			req.Header[key] = append([]string(nil), values...)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(req, attempt)
+
+		fields := requestFields(req)
+		fields[FieldAttempt] = attempt
+		if resp != nil {
+			fields[FieldStatus] = resp.StatusCode
+		}
+		if err == nil {
+			c.logger.Debug("user service request succeeded", fields)
+			return resp, nil
+		}
+		fields[FieldError] = err.Error()
+
+		retry := false
+		var delay time.Duration
+		if c.retryPolicy != nil {
+			delay, retry = c.retryPolicy.Backoff(req, resp, err, attempt)
+		}
+		switch {
+		case retry:
+			c.logger.Warn("user service request failed, retrying", fields)
+		case IsRetryable(err):
+			c.logger.Error("user service request failed", fields)
+		default:
+			c.logger.Debug("user service request failed", fields)
+		}
+
+		if !retry || !sleepContext(req.Context(), delay) {
+			return nil, err
+		}
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// attempt sends the request once, the response is returned along with the error
+// for unsuccessful status codes, its body is closed then
+func (c *HttpClient) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	var finish func(*http.Response, error)
+	if c.tracingHook != nil {
+		req, finish = c.tracingHook(req, attempt)
+	}
+
+	start := time.Now()
+	var resp *http.Response
+	err := c.runBeforeRequestHooks(req)
+	if err == nil {
+		resp, err = c.requestClient.Do(req)
+	}
+	if resp != nil {
+		c.runAfterResponseHooks(req, resp)
+	}
+	if err == nil && (resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices) &&
+		!(resp.StatusCode == http.StatusNotModified && isConditional(req)) {
+		err = newAPIError(resp)
+		resp.Body.Close()
+	}
+	if err != nil {
+		c.runErrorHooks(req, err)
+	}
+
+	if c.metricsHook != nil {
+		c.metricsHook(req, resp, err, time.Since(start))
+	}
+	if finish != nil {
+		finish(resp, err)
+	}
+
+	return resp, err
+}
+
+// enrichPlatforms fetches platforms of the users in parallel if enrichment is enabled,
+// users which already have platforms are skipped
+func (c *HttpClient) enrichPlatforms(ctx context.Context, token string, users []*User) error {
+	if c.enrichmentConcurrency <= 0 {
+		return nil
+	}
+
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.enrichmentConcurrency)
+	for _, user := range users {
+		if user.PlatformNames != nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(user *User) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.addPlatformsToUser(ctx, token, user); err != nil {
+				mu.Lock()
+				errs[user.Id] = err
+				mu.Unlock()
+			}
+		}(user)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &PlatformsError{Errors: errs}
+	}
+	return nil
+}
+
+func (c *HttpClient) addPlatformsToUser(ctx context.Context, token string, user *User) error {
+	req, err := c.requestBuilder.BuildPlatformsRequest(ctx, token, user.Id)
+	if err != nil {
+		return err
+	}
+	names, err := doConditionalRequest(c, req, decodePlatformNames, cloneStrings)
+	if err != nil {
+		return err
 	}
+	user.PlatformNames = names
 
 	return nil
 }
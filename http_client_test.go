@@ -2,12 +2,17 @@ package userclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 )
@@ -19,39 +24,44 @@ func setup() {
 	builder = HttpRequestBuilderImpl{}
 
 	builderMock = HttpRequestBuilderMock{
-		BuildLoginRequestMock: func(username string, password string) (*http.Request, error) {
+		BuildLoginRequestMock: func(ctx context.Context, username string, password string) (*http.Request, error) {
 			data := make(map[string]string)
 			data["username"] = username
 			data["password"] = password
 			dataJson, _ := json.Marshal(data)
 
 			req, _ := http.NewRequest(http.MethodPost, builderMock.RequestURL, bytes.NewBuffer(dataJson))
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildMeRequestMock: func(token string) (*http.Request, error) {
+		BuildMeRequestMock: func(ctx context.Context, token string) (*http.Request, error) {
 			req, _ := http.NewRequest(http.MethodGet, builderMock.RequestURL, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildPlatformsRequestMock: func(token, userID string) (*http.Request, error) {
+		BuildPlatformsRequestMock: func(ctx context.Context, token, userID string) (*http.Request, error) {
 			url := builderMock.RequestURL + "/platforms"
 			req, _ := http.NewRequest(http.MethodGet, url, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildLogoutRequestMock: func(token string) (*http.Request, error) {
+		BuildLogoutRequestMock: func(ctx context.Context, token string) (*http.Request, error) {
 			req, _ := http.NewRequest(http.MethodPost, builderMock.RequestURL, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildGetRequestMock: func(token, userID string) (*http.Request, error) {
+		BuildGetRequestMock: func(ctx context.Context, token, userID string) (*http.Request, error) {
 			req, _ := http.NewRequest(http.MethodGet, builderMock.RequestURL, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildGetAllRequestMock: func(token string) (*http.Request, error) {
+		BuildListUsersRequestMock: func(ctx context.Context, token string, params ListUsersParams) (*http.Request, error) {
+			url := fmt.Sprintf("%s?page=%d&per_page=%d&cursor=%s", builderMock.RequestURL, params.Page, params.PerPage, params.Cursor)
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			return req.WithContext(ctx), nil
+		},
+		BuildSearchUsersRequestMock: func(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*http.Request, error) {
 			req, _ := http.NewRequest(http.MethodGet, builderMock.RequestURL, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
-		BuildRevokedTokensRequestMock: func(token string) (*http.Request, error) {
+		BuildRevokedTokensRequestMock: func(ctx context.Context, token string) (*http.Request, error) {
 			req, _ := http.NewRequest(http.MethodGet, builderMock.RequestURL, nil)
-			return req, nil
+			return req.WithContext(ctx), nil
 		},
 	}
 
@@ -100,11 +110,76 @@ func TestUserHttpClient_AuthenticateHasReturnError(t *testing.T) {
 		t.Error("Token should be empty if we have error")
 	}
 
-	if err != ErrNotFound {
+	if !errors.Is(err, ErrNotFound) {
 		t.Error("Error messsage should be not found")
 	}
 }
 
+func TestUserHttpClient_AuthenticateInvalidToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"token": 42}`))
+		}))
+	defer ts.Close()
+
+	builderMock.RequestURL = ts.URL
+	token, err := httpClient.Authenticate("username", "password")
+	if err == nil || token != "" {
+		t.Errorf("Invalid token should fail, got %q, %v", token, err)
+	}
+}
+
+func TestUserHttpClient_Login(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":        "access",
+				"refreshToken": "refresh",
+				"expiresAt":    expiresAt,
+			})
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	login, err := client.Login(context.Background(), "username", "password")
+	if err != nil {
+		t.Fatal("Has error when testing login:", err.Error())
+	}
+	expect := LoginResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: expiresAt}
+	if !reflect.DeepEqual(*login, expect) {
+		t.Errorf("Expect %+v - Got %+v", expect, *login)
+	}
+}
+
+func TestUserHttpClient_Refresh(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/users/refresh" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["refreshToken"] != "refresh" {
+				t.Errorf("Wrong refresh token sent: %v", body)
+			}
+			w.Write([]byte(`{"accessToken": "access", "expiresIn": 3600}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	login, err := client.Refresh(context.Background(), "refresh")
+	if err != nil {
+		t.Fatal("Has error when testing refresh:", err.Error())
+	}
+	if login.AccessToken != "access" || login.RefreshToken != "" {
+		t.Errorf("Wrong tokens returned: %+v", login)
+	}
+	if d := time.Until(login.ExpiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("Wrong expiry %v", login.ExpiresAt)
+	}
+}
+
 func TestUserHttpClient_Me(t *testing.T) {
 	returnUser := User{
 		Id:        "a802918c-4471-46a1-989b-c0cf651a4b2c",
@@ -203,6 +278,183 @@ func TestUserHttpClient_FindById(t *testing.T) {
 	assertUser(t, user, &returnUser)
 }
 
+func TestUserHttpClient_ListUsers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") != "2" || r.URL.Query().Get("per_page") != "2" {
+				t.Errorf("Wrong page requested: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{
+				"data": [{"id": "3"}, {"id": "4"}],
+				"meta": {"page": 2, "perPage": 2, "total": 5, "nextCursor": "next"}
+			}`))
+		}))
+	defer ts.Close()
+
+	builderMock.RequestURL = ts.URL
+	page, err := httpClient.ListUsers(context.Background(), "This is a token string", ListUsersParams{Page: 2, PerPage: 2})
+	if err != nil {
+		t.Fatal("Has error when testing list users request:", err.Error())
+	}
+	if len(page.Users) != 2 || page.Users[0].Id != "3" || page.Users[1].Id != "4" {
+		t.Errorf("Wrong users returned: %+v", page.Users)
+	}
+	if page.Page != 2 || page.PerPage != 2 || page.Total != 5 || page.NextCursor != "next" {
+		t.Errorf("Wrong page metadata: %+v", page)
+	}
+}
+
+func TestUserHttpClient_SearchUsers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": [{"id": "1", "roles": ["PlatformWarehouse"], "active": true}]}`))
+		}))
+	defer ts.Close()
+
+	active := true
+	filter := UserFilter{Roles: []string{RolePlatformWarehouse}, Active: &active}
+
+	builderMock.RequestURL = ts.URL
+	page, err := httpClient.SearchUsers(context.Background(), "This is a token string", filter, ListUsersParams{})
+	if err != nil {
+		t.Fatal("Has error when testing search users request:", err.Error())
+	}
+	if len(page.Users) != 1 || page.Users[0].Id != "1" || !page.Users[0].HasRole(RolePlatformWarehouse) {
+		t.Errorf("Wrong users returned: %+v", page.Users)
+	}
+}
+
+func TestUserHttpClient_FindByIds(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			var users []User
+			for _, id := range r.URL.Query()["id"] {
+				if id != "missing" {
+					users = append(users, User{Id: id})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string][]User{"data": users})
+		}))
+	defer ts.Close()
+
+	ids := []string{"missing"}
+	for i := 0; i < 150; i++ {
+		ids = append(ids, fmt.Sprint(i))
+	}
+	ids = append(ids, "1")
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	users, err := client.FindByIds(context.Background(), "token", ids)
+	if err != nil {
+		t.Fatal("Has error when testing find by ids request:", err.Error())
+	}
+	if requests != 2 {
+		t.Errorf("should be 2 requests, made %d", requests)
+	}
+	if len(users) != 151 {
+		t.Errorf("should be 151 users, returned %d", len(users))
+	}
+	if u, ok := users["missing"]; !ok || u != nil {
+		t.Error("Not found user should be mapped to nil")
+	}
+	if u := users["149"]; u == nil || u.Id != "149" {
+		t.Errorf("Wrong user returned: %+v", u)
+	}
+}
+
+func TestUserHttpClient_FindAll(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			switch r.URL.Query().Get("page") {
+			case "0":
+				w.Write([]byte(`{"data": [{"id": "1"}, {"id": "2"}], "meta": {"page": 1, "perPage": 2, "total": 3}}`))
+			case "2":
+				w.Write([]byte(`{"data": [{"id": "3"}], "meta": {"page": 2, "perPage": 2, "total": 3}}`))
+			default:
+				t.Errorf("Unexpected page requested: %s", r.URL.RawQuery)
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+	defer ts.Close()
+
+	builderMock.RequestURL = ts.URL
+	users, err := httpClient.FindAll("This is a token string")
+	if err != nil {
+		t.Fatal("Has error when testing find all request:", err.Error())
+	}
+	if len(users) != 3 {
+		t.Fatalf("should be 3 users, returned %d", len(users))
+	}
+	for i, u := range users {
+		if u.Id != fmt.Sprint(i+1) {
+			t.Errorf("Wrong user id '%s' at %d", u.Id, i)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("should be 2 requests, made %d", requests)
+	}
+}
+
+func TestUserHttpClient_FindAllPageIgnored(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests > 2 {
+				t.Fatalf("Pages should stop repeating, made %d requests", requests)
+			}
+			if r.URL.Query().Get("per_page") != "100" {
+				t.Errorf("Page size should be requested, got %s", r.URL.RawQuery)
+			}
+			// legacy endpoint returns all users regardless of page
+			users := make([]User, 150)
+			for i := range users {
+				users[i].Id = fmt.Sprint(i + 1)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": users})
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	users, err := client.FindAll("token")
+	if err != nil {
+		t.Fatal("Has error when testing find all request:", err.Error())
+	}
+	if len(users) != 150 {
+		t.Errorf("should be 150 users, returned %d", len(users))
+	}
+}
+
+func TestUserHttpClient_FindAllPageSizeCapped(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// server returns at most 50 of 120 users per page
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			users := []User{}
+			for id := (page-1)*50 + 1; id <= page*50 && id <= 120; id++ {
+				users = append(users, User{Id: fmt.Sprint(id)})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": users})
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	users, err := client.FindAll("token")
+	if err != nil {
+		t.Fatal("Has error when testing find all request:", err.Error())
+	}
+	if len(users) != 120 {
+		t.Errorf("should be 120 users, returned %d", len(users))
+	}
+}
+
 func TestUserHttpClient_RevokedTokens(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -235,6 +487,276 @@ func TestUserHttpClient_RevokedTokens(t *testing.T) {
 	}
 }
 
+func TestUserHttpClient_MeContextCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	builderMock.RequestURL = ts.URL
+	user, err := httpClient.MeContext(ctx, "This is a token string")
+	if err == nil {
+		t.Fatal("Should return error when context is done")
+	}
+	if user != nil {
+		t.Error("Shouldn't return user when context is done")
+	}
+}
+
+func TestUserHttpClient_CreateUser(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/users" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"id": "new-id", "username": "john"}}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	user, err := client.CreateUser(context.Background(), "token", CreateUserInput{Username: "john"})
+	if err != nil {
+		t.Fatal("Has error when testing create user request:", err.Error())
+	}
+	if user.Id != "new-id" || user.Username != "john" {
+		t.Errorf("Wrong user returned: %+v", user)
+	}
+}
+
+func TestUserHttpClient_CreateUserConflict(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	user, err := client.CreateUser(context.Background(), "token", CreateUserInput{Username: "john"})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Error should be conflict, got %v", err)
+	}
+	if user != nil {
+		t.Error("Shouldn't return user on error")
+	}
+}
+
+func TestUserHttpClient_DeleteUser(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete || r.URL.Path != "/users/id" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	if err := client.DeleteUser(context.Background(), "token", "id"); err != nil {
+		t.Error("Has error when testing delete user request:", err.Error())
+	}
+}
+
+func TestUserHttpClient_GrantRoles(t *testing.T) {
+	var granted []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("Unexpected method %s", r.Method)
+			}
+			granted = append(granted, r.URL.Path)
+			switch r.URL.Path {
+			case "/users/id/roles/Admin":
+				// already granted
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error": {"code": "assignment_exists", "message": "role is assigned"}}`))
+			case "/users/locked/roles/Admin":
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error": {"code": "user_locked", "message": "user is being updated"}}`))
+			}
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	err := client.GrantRoles(context.Background(), "token", "id", RoleAdmin, RoleAccessAdmin)
+	if err != nil {
+		t.Fatal("Has error when testing grant roles request:", err.Error())
+	}
+	if !reflect.DeepEqual(granted, []string{"/users/id/roles/Admin", "/users/id/roles/AccessAdmin"}) {
+		t.Errorf("Wrong requests made: %v", granted)
+	}
+
+	err = client.GrantRoles(context.Background(), "token", "locked", RoleAdmin)
+	var assignmentErr *AssignmentError
+	if !errors.As(err, &assignmentErr) || !errors.Is(err, ErrConflict) {
+		t.Errorf("Unrelated conflict should fail, got %v", err)
+	}
+}
+
+func TestUserHttpClient_RevokeRoles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			if r.URL.Path == "/users/id/roles/Admin" {
+				// not granted
+				w.Write([]byte(`{"error": {"code": "assignment_not_found", "message": "role isn't assigned"}}`))
+			} else {
+				w.Write([]byte(`{"error": {"code": "user_not_found", "message": "user doesn't exist"}}`))
+			}
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	if err := client.RevokeRoles(context.Background(), "token", "id", RoleAdmin); err != nil {
+		t.Error("Revoking not granted role should succeed, got", err)
+	}
+
+	err := client.RevokeRoles(context.Background(), "token", "unknown", RoleAdmin)
+	var assignmentErr *AssignmentError
+	if !errors.As(err, &assignmentErr) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Revoking role of unknown user should fail, got %v", err)
+	}
+}
+
+func TestUserHttpClient_DetachPlatformsError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/users/id/platforms/ALIBABA" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	err := client.DetachPlatforms(context.Background(), "token", "id", "OMS_VN", "ALIBABA", "LAZADA")
+	assignmentErr, ok := err.(*AssignmentError)
+	if !ok {
+		t.Fatalf("Error should be assignment error, got %v", err)
+	}
+	if assignmentErr.Op != "detach platform" || assignmentErr.Name != "ALIBABA" || assignmentErr.UserId != "id" {
+		t.Errorf("Wrong assignment error: %+v", assignmentErr)
+	}
+	if !errors.Is(assignmentErr, ErrUnauthorized) {
+		t.Errorf("Wrapped error should be unauthorized, got %v", assignmentErr.Err)
+	}
+}
+
+func TestUserHttpClient_ChangePassword(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/users/me/password" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("Wrong authorization header %q", r.Header.Get("Authorization"))
+			}
+			var input ChangePasswordInput
+			json.NewDecoder(r.Body).Decode(&input)
+			if input.OldPassword != "old" || input.NewPassword != "new" {
+				t.Errorf("Wrong input sent: %+v", input)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	err := client.ChangePassword(context.Background(), "token", ChangePasswordInput{OldPassword: "old", NewPassword: "new"})
+	if err != nil {
+		t.Error("Has error when testing change password request:", err.Error())
+	}
+}
+
+func TestUserHttpClient_PasswordReset(t *testing.T) {
+	var requested []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.Path)
+			if r.Header.Get("Authorization") != "" {
+				t.Error("Password reset shouldn't be authenticated")
+			}
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.URL.Path == "/users/password-reset" && body["email"] != "john@example.com" {
+				t.Errorf("Wrong reset request sent: %v", body)
+			}
+			if r.URL.Path == "/users/password-reset/confirm" && (body["token"] != "reset" || body["password"] != "new") {
+				t.Errorf("Wrong reset confirmation sent: %v", body)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	if err := client.RequestPasswordReset(context.Background(), PasswordResetInput{Email: "john@example.com"}); err != nil {
+		t.Error("Has error when testing password reset request:", err.Error())
+	}
+	if err := client.ConfirmPasswordReset(context.Background(), ConfirmPasswordResetInput{ResetToken: "reset", NewPassword: "new"}); err != nil {
+		t.Error("Has error when testing password reset confirmation:", err.Error())
+	}
+	if !reflect.DeepEqual(requested, []string{"/users/password-reset", "/users/password-reset/confirm"}) {
+		t.Errorf("Wrong requests made: %v", requested)
+	}
+}
+
+func TestUserHttpClient_ChangePasswordValidation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error": {"code": "validation_failed", "message": "invalid password", "fields": [{"field": "newPassword", "code": "too_short", "message": "must be at least 8 characters"}]}}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	err := client.ChangePassword(context.Background(), "token", ChangePasswordInput{OldPassword: "old", NewPassword: "new"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Error should be validation error, got %v", err)
+	}
+	fields := ValidationErrors(err)
+	if !reflect.DeepEqual(fields, []ValidationError{{"newPassword", "too_short", "must be at least 8 characters"}}) {
+		t.Errorf("Wrong field errors: %v", fields)
+	}
+	expect := "POST /users/me/password: 422 validation failed: invalid password: newPassword: must be at least 8 characters"
+	if err.Error() != expect {
+		t.Errorf("Expect %q - Got %q", expect, err.Error())
+	}
+}
+
+func TestUserHttpClient_APIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error": {"code": "email_taken", "message": "email is already taken"}}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	_, err := client.CreateUser(context.Background(), "token", CreateUserInput{Username: "john"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Error should be APIError, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("Error should match ErrValidation")
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Method != http.MethodPost || apiErr.Path != "/users" {
+		t.Errorf("Wrong request metadata: %+v", apiErr)
+	}
+	if apiErr.Code != "email_taken" || apiErr.Message != "email is already taken" {
+		t.Errorf("Wrong server error: %+v", apiErr)
+	}
+	if apiErr.Retryable() {
+		t.Error("Validation error shouldn't be retryable")
+	}
+	if apiErr.Error() != "POST /users: 422 validation failed: email is already taken" {
+		t.Errorf("Wrong error message: %s", apiErr.Error())
+	}
+}
+
 func assertUser(t *testing.T, user, returnUser *User) {
 	if user.Id != returnUser.Id {
 		t.Errorf("Return user id '%s' is invalid, expected '%s'", user.Id, returnUser.Id)
@@ -252,3 +774,122 @@ func assertUser(t *testing.T, user, returnUser *User) {
 		t.Errorf("Return user platfrom names '%v' are invalid, expected '%s'", user.PlatformNames, returnUser.PlatformNames)
 	}
 }
+
+func TestUserHttpClient_EmbeddedPlatforms(t *testing.T) {
+	platformRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/users/me":
+				if r.URL.Query().Get("include") != "platforms" {
+					t.Errorf("Wrong query %s", r.URL.RawQuery)
+				}
+				w.Write([]byte(`{"data": {"id": "1", "platforms": [{"name": "vn"}, {"name": "th"}]}}`))
+			case "/users/2":
+				// include isn't supported
+				w.Write([]byte(`{"data": {"id": "2"}}`))
+			case "/users/2/platforms":
+				platformRequests++
+				w.Write([]byte(`{"data": [{"name": "sg"}]}`))
+			default:
+				t.Errorf("Unexpected request %s", r.URL.Path)
+			}
+		}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, WithEmbeddedPlatforms())
+
+	user, err := client.Me("token")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if !reflect.DeepEqual(user.PlatformNames, []string{"vn", "th"}) {
+		t.Errorf("Wrong platforms %v", user.PlatformNames)
+	}
+
+	user, err = client.FindById("token", "2")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if platformRequests != 1 || !reflect.DeepEqual(user.PlatformNames, []string{"sg"}) {
+		t.Errorf("Wrong platforms %v", user.PlatformNames)
+	}
+}
+
+func TestUserHttpClient_StrictPlatforms(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/users/1/platforms" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"data": {"id": "1"}}`))
+		}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL)
+	user, err := client.FindById("token", "1")
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if user.PlatformNames != nil {
+		t.Errorf("Wrong platforms %v", user.PlatformNames)
+	}
+
+	client, _ = NewClient(ts.URL, WithStrictPlatforms())
+	if _, err := client.FindById("token", "1"); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+}
+
+func TestUserHttpClient_PlatformEnrichment(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/users" {
+				w.Write([]byte(`{"data": [{"id": "1"}, {"id": "2"}, {"id": "3"}, {"id": "4"}]}`))
+				return
+			}
+
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			if r.URL.Path == "/users/3/platforms" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{"data": [{"name": "vn"}]}`))
+		}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, WithPlatformEnrichment(2))
+	users, err := client.FindAll("token")
+
+	var platformsErr *PlatformsError
+	if !errors.As(err, &platformsErr) || !errors.Is(err, ErrServiceUnavailable) {
+		t.Fatalf("Wrong error '%v' returned", err)
+	}
+	if len(platformsErr.Errors) != 1 || platformsErr.Errors["3"] == nil {
+		t.Errorf("Wrong failed users %v", platformsErr.Errors)
+	}
+	if len(users) != 4 {
+		t.Fatalf("Wrong number of users %d", len(users))
+	}
+	for _, u := range users {
+		if u.Id == "3" && u.PlatformNames != nil || u.Id != "3" && len(u.PlatformNames) != 1 {
+			t.Errorf("Wrong platforms of user %s: %v", u.Id, u.PlatformNames)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Wrong number of parallel requests %d", maxInFlight)
+	}
+}
@@ -2,9 +2,13 @@ package userclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
@@ -12,26 +16,55 @@ const (
 	mePath               = "/users/me"
 	platformsPath        = "/users/%s/platforms"
 	logoutPath           = "/users/logout"
+	refreshPath          = "/users/refresh"
 	getPath              = "/users/%s"
-	getAllPath           = "/users?per_page=10000"
+	createPath           = "/users"
+	deactivatePath       = "/users/%s/deactivate"
+	rolePath             = "/users/%s/roles/%s"
+	platformPath         = "/users/%s/platforms/%s"
+	listPath             = "/users"
 	getRevokedTokensPath = "/users/revoked-tokens"
+	healthPath           = "/health"
+	changePasswordPath   = "/users/me/password"
+	passwordResetPath    = "/users/password-reset"
+	confirmResetPath     = "/users/password-reset/confirm"
 )
 
 type HttpRequestBuilder interface {
-	BuildLoginRequest(username string, password string) (*http.Request, error)
-	BuildMeRequest(token string) (*http.Request, error)
-	BuildPlatformsRequest(token, userID string) (*http.Request, error)
-	BuildLogoutRequest(token string) (*http.Request, error)
-	BuildGetRequest(token, userID string) (*http.Request, error)
-	BuildGetAllRequest(token string) (*http.Request, error)
-	BuildRevokedTokensRequest(token string) (*http.Request, error)
+	BuildLoginRequest(ctx context.Context, username string, password string) (*http.Request, error)
+	BuildRefreshRequest(ctx context.Context, refreshToken string) (*http.Request, error)
+	BuildMeRequest(ctx context.Context, token string) (*http.Request, error)
+	BuildPlatformsRequest(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildLogoutRequest(ctx context.Context, token string) (*http.Request, error)
+	BuildGetRequest(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildListUsersRequest(ctx context.Context, token string, params ListUsersParams) (*http.Request, error)
+	BuildSearchUsersRequest(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*http.Request, error)
+	BuildFindByIdsRequest(ctx context.Context, token string, userIDs []string) (*http.Request, error)
+	BuildRevokedTokensRequest(ctx context.Context, token string) (*http.Request, error)
+
+	BuildCreateUserRequest(ctx context.Context, token string, input CreateUserInput) (*http.Request, error)
+	BuildUpdateUserRequest(ctx context.Context, token, userID string, input UpdateUserInput) (*http.Request, error)
+	BuildPatchUserRequest(ctx context.Context, token, userID string, input PatchUserInput) (*http.Request, error)
+	BuildDeactivateUserRequest(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildDeleteUserRequest(ctx context.Context, token, userID string) (*http.Request, error)
+
+	BuildGrantRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error)
+	BuildRevokeRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error)
+	BuildAttachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error)
+	BuildDetachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error)
+
+	BuildChangePasswordRequest(ctx context.Context, token string, input ChangePasswordInput) (*http.Request, error)
+	BuildPasswordResetRequest(ctx context.Context, input PasswordResetInput) (*http.Request, error)
+	BuildConfirmPasswordResetRequest(ctx context.Context, input ConfirmPasswordResetInput) (*http.Request, error)
+
+	BuildHealthRequest(ctx context.Context) (*http.Request, error)
 }
 
 type HttpRequestBuilderImpl struct {
 	BaseURL string
 }
 
-func (rb *HttpRequestBuilderImpl) BuildLoginRequest(username string, password string) (*http.Request, error) {
+func (rb *HttpRequestBuilderImpl) BuildLoginRequest(ctx context.Context, username string, password string) (*http.Request, error) {
 	data := make(map[string]string)
 	data["username"] = username
 	data["password"] = password
@@ -41,45 +74,188 @@ func (rb *HttpRequestBuilderImpl) BuildLoginRequest(username string, password st
 		return nil, err
 	}
 
-	return rb.build(http.MethodPost, authenticatePath, dataJson)
+	return rb.build(ctx, http.MethodPost, authenticatePath, dataJson)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildRefreshRequest(ctx context.Context, refreshToken string) (*http.Request, error) {
+	dataJson, err := json.Marshal(map[string]string{"refreshToken": refreshToken})
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.build(ctx, http.MethodPost, refreshPath, dataJson)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildMeRequest(token string) (*http.Request, error) {
-	return rb.buildWithAuth(http.MethodGet, mePath, nil, token)
+func (rb *HttpRequestBuilderImpl) BuildMeRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.buildWithAuth(ctx, http.MethodGet, mePath, nil, token)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildPlatformsRequest(token, userID string) (*http.Request, error) {
+func (rb *HttpRequestBuilderImpl) BuildPlatformsRequest(ctx context.Context, token, userID string) (*http.Request, error) {
 	path := fmt.Sprintf(platformsPath, userID)
-	return rb.buildWithAuth(http.MethodGet, path, nil, token)
+	return rb.buildWithAuth(ctx, http.MethodGet, path, nil, token)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildLogoutRequest(token string) (*http.Request, error) {
-	return rb.buildWithAuth(http.MethodPost, logoutPath, nil, token)
+func (rb *HttpRequestBuilderImpl) BuildLogoutRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.buildWithAuth(ctx, http.MethodPost, logoutPath, nil, token)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildGetRequest(token, userID string) (*http.Request, error) {
+func (rb *HttpRequestBuilderImpl) BuildGetRequest(ctx context.Context, token, userID string) (*http.Request, error) {
 	path := fmt.Sprintf(getPath, userID)
-	return rb.buildWithAuth(http.MethodGet, path, nil, token)
+	return rb.buildWithAuth(ctx, http.MethodGet, path, nil, token)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildGetAllRequest(token string) (*http.Request, error) {
-	return rb.buildWithAuth(http.MethodGet, getAllPath, nil, token)
+func (rb *HttpRequestBuilderImpl) BuildListUsersRequest(ctx context.Context, token string, params ListUsersParams) (*http.Request, error) {
+	query := url.Values{}
+	addPageQuery(query, params)
+
+	return rb.buildWithAuth(ctx, http.MethodGet, withQuery(listPath, query), nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildSearchUsersRequest(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*http.Request, error) {
+	query := url.Values{}
+	for _, role := range filter.Roles {
+		query.Add("role", role)
+	}
+	for _, platform := range filter.PlatformNames {
+		query.Add("platform", platform)
+	}
+	if filter.Active != nil {
+		query.Set("active", strconv.FormatBool(*filter.Active))
+	}
+	if filter.UsernamePrefix != "" {
+		query.Set("username_prefix", filter.UsernamePrefix)
+	}
+	if filter.EmailPrefix != "" {
+		query.Set("email_prefix", filter.EmailPrefix)
+	}
+	if !filter.UpdatedSince.IsZero() {
+		query.Set("updated_since", filter.UpdatedSince.UTC().Format(time.RFC3339))
+	}
+	addPageQuery(query, params)
+
+	return rb.buildWithAuth(ctx, http.MethodGet, withQuery(listPath, query), nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildFindByIdsRequest(ctx context.Context, token string, userIDs []string) (*http.Request, error) {
+	query := url.Values{"id": userIDs}
+	query.Set("per_page", strconv.Itoa(len(userIDs)))
+
+	return rb.buildWithAuth(ctx, http.MethodGet, withQuery(listPath, query), nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildRevokedTokensRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.buildWithAuth(ctx, http.MethodGet, getRevokedTokensPath, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildCreateUserRequest(ctx context.Context, token string, input CreateUserInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.buildWithAuth(ctx, http.MethodPost, createPath, dataJson, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildUpdateUserRequest(ctx context.Context, token, userID string, input UpdateUserInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(getPath, userID)
+	return rb.buildWithAuth(ctx, http.MethodPut, path, dataJson, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildPatchUserRequest(ctx context.Context, token, userID string, input PatchUserInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(getPath, userID)
+	return rb.buildWithAuth(ctx, http.MethodPatch, path, dataJson, token)
 }
 
-func (rb *HttpRequestBuilderImpl) BuildRevokedTokensRequest(token string) (*http.Request, error) {
-	return rb.buildWithAuth(http.MethodGet, getRevokedTokensPath, nil, token)
+func (rb *HttpRequestBuilderImpl) BuildDeactivateUserRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	path := fmt.Sprintf(deactivatePath, userID)
+	return rb.buildWithAuth(ctx, http.MethodPost, path, nil, token)
 }
 
-func (rb *HttpRequestBuilderImpl) build(method string, path string, data []byte) (*http.Request, error) {
-	return http.NewRequest(
+func (rb *HttpRequestBuilderImpl) BuildDeleteUserRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	path := fmt.Sprintf(getPath, userID)
+	return rb.buildWithAuth(ctx, http.MethodDelete, path, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildGrantRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error) {
+	path := fmt.Sprintf(rolePath, userID, url.PathEscape(role))
+	return rb.buildWithAuth(ctx, http.MethodPut, path, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildRevokeRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error) {
+	path := fmt.Sprintf(rolePath, userID, url.PathEscape(role))
+	return rb.buildWithAuth(ctx, http.MethodDelete, path, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildAttachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error) {
+	path := fmt.Sprintf(platformPath, userID, url.PathEscape(platformName))
+	return rb.buildWithAuth(ctx, http.MethodPut, path, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildDetachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error) {
+	path := fmt.Sprintf(platformPath, userID, url.PathEscape(platformName))
+	return rb.buildWithAuth(ctx, http.MethodDelete, path, nil, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildChangePasswordRequest(ctx context.Context, token string, input ChangePasswordInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.buildWithAuth(ctx, http.MethodPost, changePasswordPath, dataJson, token)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildPasswordResetRequest(ctx context.Context, input PasswordResetInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.build(ctx, http.MethodPost, passwordResetPath, dataJson)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildConfirmPasswordResetRequest(ctx context.Context, input ConfirmPasswordResetInput) (*http.Request, error) {
+	dataJson, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.build(ctx, http.MethodPost, confirmResetPath, dataJson)
+}
+
+func (rb *HttpRequestBuilderImpl) BuildHealthRequest(ctx context.Context) (*http.Request, error) {
+	return rb.build(ctx, http.MethodGet, healthPath, nil)
+}
+
+func (rb *HttpRequestBuilderImpl) build(ctx context.Context, method string, path string, data []byte) (*http.Request, error) {
+	req, err := http.NewRequest(
 		method,
 		fmt.Sprintf("%s%s", rb.BaseURL, path),
 		bytes.NewBuffer(data),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req.WithContext(ctx), nil
 }
 
-func (rb *HttpRequestBuilderImpl) buildWithAuth(method string, path string, data []byte, token string) (*http.Request, error) {
-	req, err := rb.build(method, path, data)
+func (rb *HttpRequestBuilderImpl) buildWithAuth(ctx context.Context, method string, path string, data []byte, token string) (*http.Request, error) {
+	req, err := rb.build(ctx, method, path, data)
 	if err != nil {
 		return nil, err
 	}
@@ -88,3 +264,23 @@ func (rb *HttpRequestBuilderImpl) buildWithAuth(method string, path string, data
 
 	return req, nil
 }
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// addPageQuery sets the page size always, defaultPerPage is used if it isn't set
+func addPageQuery(query url.Values, params ListUsersParams) {
+	if params.PerPage <= 0 {
+		params.PerPage = defaultPerPage
+	}
+	query.Set("per_page", strconv.Itoa(params.PerPage))
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	} else if params.Page > 0 {
+		query.Set("page", strconv.Itoa(params.Page))
+	}
+}
@@ -1,45 +1,115 @@
 package userclient
 
 import (
+	"context"
 	"net/http"
 )
 
 type HttpRequestBuilderMock struct {
 	RequestURL                    string
-	BuildLoginRequestMock         func(username string, password string) (*http.Request, error)
-	BuildMeRequestMock            func(token string) (*http.Request, error)
-	BuildPlatformsRequestMock     func(token, userID string) (*http.Request, error)
-	BuildLogoutRequestMock        func(token string) (*http.Request, error)
-	BuildGetRequestMock           func(token, userID string) (*http.Request, error)
-	BuildGetAllRequestMock        func(token string) (*http.Request, error)
-	BuildRevokedTokensRequestMock func(token string) (*http.Request, error)
+	BuildLoginRequestMock         func(ctx context.Context, username string, password string) (*http.Request, error)
+	BuildRefreshRequestMock       func(ctx context.Context, refreshToken string) (*http.Request, error)
+	BuildMeRequestMock            func(ctx context.Context, token string) (*http.Request, error)
+	BuildPlatformsRequestMock     func(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildLogoutRequestMock        func(ctx context.Context, token string) (*http.Request, error)
+	BuildGetRequestMock           func(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildListUsersRequestMock     func(ctx context.Context, token string, params ListUsersParams) (*http.Request, error)
+	BuildSearchUsersRequestMock   func(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*http.Request, error)
+	BuildFindByIdsRequestMock     func(ctx context.Context, token string, userIDs []string) (*http.Request, error)
+	BuildRevokedTokensRequestMock func(ctx context.Context, token string) (*http.Request, error)
+
+	BuildCreateUserRequestMock     func(ctx context.Context, token string, input CreateUserInput) (*http.Request, error)
+	BuildUpdateUserRequestMock     func(ctx context.Context, token, userID string, input UpdateUserInput) (*http.Request, error)
+	BuildPatchUserRequestMock      func(ctx context.Context, token, userID string, input PatchUserInput) (*http.Request, error)
+	BuildDeactivateUserRequestMock func(ctx context.Context, token, userID string) (*http.Request, error)
+	BuildDeleteUserRequestMock     func(ctx context.Context, token, userID string) (*http.Request, error)
+
+	BuildGrantRoleRequestMock      func(ctx context.Context, token, userID, role string) (*http.Request, error)
+	BuildRevokeRoleRequestMock     func(ctx context.Context, token, userID, role string) (*http.Request, error)
+	BuildAttachPlatformRequestMock func(ctx context.Context, token, userID, platformName string) (*http.Request, error)
+	BuildDetachPlatformRequestMock func(ctx context.Context, token, userID, platformName string) (*http.Request, error)
+
+	BuildChangePasswordRequestMock       func(ctx context.Context, token string, input ChangePasswordInput) (*http.Request, error)
+	BuildPasswordResetRequestMock        func(ctx context.Context, input PasswordResetInput) (*http.Request, error)
+	BuildConfirmPasswordResetRequestMock func(ctx context.Context, input ConfirmPasswordResetInput) (*http.Request, error)
+
+	BuildHealthRequestMock func(ctx context.Context) (*http.Request, error)
+}
+
+func (rb *HttpRequestBuilderMock) BuildLoginRequest(ctx context.Context, username string, password string) (*http.Request, error) {
+	return rb.BuildLoginRequestMock(ctx, username, password)
+}
+
+func (rb *HttpRequestBuilderMock) BuildRefreshRequest(ctx context.Context, refreshToken string) (*http.Request, error) {
+	return rb.BuildRefreshRequestMock(ctx, refreshToken)
+}
+
+func (rb *HttpRequestBuilderMock) BuildMeRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.BuildMeRequestMock(ctx, token)
 }
 
-func (rb *HttpRequestBuilderMock) BuildLoginRequest(username string, password string) (*http.Request, error) {
-	return rb.BuildLoginRequestMock(username, password)
+func (rb *HttpRequestBuilderMock) BuildPlatformsRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	return rb.BuildPlatformsRequestMock(ctx, token, userID)
 }
 
-func (rb *HttpRequestBuilderMock) BuildMeRequest(token string) (*http.Request, error) {
-	return rb.BuildMeRequestMock(token)
+func (rb *HttpRequestBuilderMock) BuildLogoutRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.BuildLogoutRequestMock(ctx, token)
 }
 
-func (rb *HttpRequestBuilderMock) BuildPlatformsRequest(token, userID string) (*http.Request, error) {
-	return rb.BuildPlatformsRequestMock(token, userID)
+func (rb *HttpRequestBuilderMock) BuildGetRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	return rb.BuildGetRequestMock(ctx, token, userID)
 }
 
-func (rb *HttpRequestBuilderMock) BuildLogoutRequest(token string) (*http.Request, error) {
-	return rb.BuildLogoutRequestMock(token)
+func (rb *HttpRequestBuilderMock) BuildListUsersRequest(ctx context.Context, token string, params ListUsersParams) (*http.Request, error) {
+	return rb.BuildListUsersRequestMock(ctx, token, params)
 }
 
-func (rb *HttpRequestBuilderMock) BuildGetRequest(token, userID string) (*http.Request, error) {
-	return rb.BuildGetRequestMock(token, userID)
+func (rb *HttpRequestBuilderMock) BuildSearchUsersRequest(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (*http.Request, error) {
+	return rb.BuildSearchUsersRequestMock(ctx, token, filter, params)
 }
-func (rb *HttpRequestBuilderMock) BuildGetAllRequest(token string) (*http.Request, error) {
-	return rb.BuildGetAllRequestMock(token)
+
+func (rb *HttpRequestBuilderMock) BuildFindByIdsRequest(ctx context.Context, token string, userIDs []string) (*http.Request, error) {
+	return rb.BuildFindByIdsRequestMock(ctx, token, userIDs)
+}
+
+func (rb *HttpRequestBuilderMock) BuildRevokedTokensRequest(ctx context.Context, token string) (*http.Request, error) {
+	return rb.BuildRevokedTokensRequestMock(ctx, token)
+}
+
+func (rb *HttpRequestBuilderMock) BuildCreateUserRequest(ctx context.Context, token string, input CreateUserInput) (*http.Request, error) {
+	return rb.BuildCreateUserRequestMock(ctx, token, input)
+}
+
+func (rb *HttpRequestBuilderMock) BuildUpdateUserRequest(ctx context.Context, token, userID string, input UpdateUserInput) (*http.Request, error) {
+	return rb.BuildUpdateUserRequestMock(ctx, token, userID, input)
+}
+
+func (rb *HttpRequestBuilderMock) BuildPatchUserRequest(ctx context.Context, token, userID string, input PatchUserInput) (*http.Request, error) {
+	return rb.BuildPatchUserRequestMock(ctx, token, userID, input)
 }
 
-func (rb *HttpRequestBuilderMock) BuildRevokedTokensRequest(token string) (*http.Request, error) {
-	return rb.BuildRevokedTokensRequestMock(token)
+func (rb *HttpRequestBuilderMock) BuildDeactivateUserRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	return rb.BuildDeactivateUserRequestMock(ctx, token, userID)
+}
+
+func (rb *HttpRequestBuilderMock) BuildDeleteUserRequest(ctx context.Context, token, userID string) (*http.Request, error) {
+	return rb.BuildDeleteUserRequestMock(ctx, token, userID)
+}
+
+func (rb *HttpRequestBuilderMock) BuildGrantRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error) {
+	return rb.BuildGrantRoleRequestMock(ctx, token, userID, role)
+}
+
+func (rb *HttpRequestBuilderMock) BuildRevokeRoleRequest(ctx context.Context, token, userID, role string) (*http.Request, error) {
+	return rb.BuildRevokeRoleRequestMock(ctx, token, userID, role)
+}
+
+func (rb *HttpRequestBuilderMock) BuildAttachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error) {
+	return rb.BuildAttachPlatformRequestMock(ctx, token, userID, platformName)
+}
+
+func (rb *HttpRequestBuilderMock) BuildDetachPlatformRequest(ctx context.Context, token, userID, platformName string) (*http.Request, error) {
+	return rb.BuildDetachPlatformRequestMock(ctx, token, userID, platformName)
 }
 
 type CacheMock struct {
@@ -57,3 +127,19 @@ func (c *CacheMock) Set(key string, obj interface{}) error {
 func (c *CacheMock) Delete(key string) error {
 	return c.DeleteFn(key)
 }
+
+func (rb *HttpRequestBuilderMock) BuildChangePasswordRequest(ctx context.Context, token string, input ChangePasswordInput) (*http.Request, error) {
+	return rb.BuildChangePasswordRequestMock(ctx, token, input)
+}
+
+func (rb *HttpRequestBuilderMock) BuildPasswordResetRequest(ctx context.Context, input PasswordResetInput) (*http.Request, error) {
+	return rb.BuildPasswordResetRequestMock(ctx, input)
+}
+
+func (rb *HttpRequestBuilderMock) BuildConfirmPasswordResetRequest(ctx context.Context, input ConfirmPasswordResetInput) (*http.Request, error) {
+	return rb.BuildConfirmPasswordResetRequestMock(ctx, input)
+}
+
+func (rb *HttpRequestBuilderMock) BuildHealthRequest(ctx context.Context) (*http.Request, error) {
+	return rb.BuildHealthRequestMock(ctx)
+}
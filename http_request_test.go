@@ -1,15 +1,20 @@
 package userclient
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
+	"time"
 )
 
 var builder HttpRequestBuilderImpl
 
 func TestRequestBuilder_BuildLoginRequest(t *testing.T) {
-	req, err := builder.BuildLoginRequest("admin", "123")
+	req, err := builder.BuildLoginRequest(context.Background(), "admin", "123")
 
 	if err != nil {
 		t.Error("Has error when creating user login request!")
@@ -27,7 +32,7 @@ func TestRequestBuilder_BuildLoginRequest(t *testing.T) {
 func TestRequestBuilder_BuildMeRequest(t *testing.T) {
 	token := "this is a very long token"
 
-	req, err := builder.BuildMeRequest(token)
+	req, err := builder.BuildMeRequest(context.Background(), token)
 
 	if err != nil {
 		t.Error("Has error when creatinge me request!")
@@ -50,7 +55,7 @@ func TestRequestBuilder_BuildPlatformsRequest(t *testing.T) {
 	token := "this is a very long token"
 	userID := "correct-id"
 
-	req, err := builder.BuildPlatformsRequest(token, userID)
+	req, err := builder.BuildPlatformsRequest(context.Background(), token, userID)
 
 	if err != nil {
 		t.Error("Has error when creating platforms request!")
@@ -72,7 +77,7 @@ func TestRequestBuilder_BuildPlatformsRequest(t *testing.T) {
 func TestRequestBuilder_BuildLogoutRequest(t *testing.T) {
 	token := "this is a very long token"
 
-	req, err := builder.BuildLogoutRequest(token)
+	req, err := builder.BuildLogoutRequest(context.Background(), token)
 
 	if err != nil {
 		t.Error("Has error when creatinge logout request!")
@@ -94,7 +99,7 @@ func TestRequestBuilder_BuildLogoutRequest(t *testing.T) {
 func TestRequestBuilder_BuildGetRequest(t *testing.T) {
 	token := "this is a very long token"
 
-	req, err := builder.BuildGetRequest(token, "correct-user-id")
+	req, err := builder.BuildGetRequest(context.Background(), token, "correct-user-id")
 
 	if err != nil {
 		t.Error("Has error when creating get request!")
@@ -113,32 +118,107 @@ func TestRequestBuilder_BuildGetRequest(t *testing.T) {
 	}
 }
 
-func TestRequestBuilder_BuildGetAllRequest(t *testing.T) {
+func TestRequestBuilder_BuildListUsersRequest(t *testing.T) {
 	token := "this is a very long token"
 
-	req, err := builder.BuildGetAllRequest(token)
+	cases := []struct {
+		Params ListUsersParams
+		Expect string
+	}{
+		{ListUsersParams{}, "/users?per_page=100"},
+		{ListUsersParams{Page: 2, PerPage: 50}, "/users?page=2&per_page=50"},
+		{ListUsersParams{Page: 2, PerPage: 50, Cursor: "abc"}, "/users?cursor=abc&per_page=50"},
+	}
+
+	for _, c := range cases {
+		req, err := builder.BuildListUsersRequest(context.Background(), token, c.Params)
+
+		if err != nil {
+			t.Fatal("Has error when creating list users request!")
+		}
+
+		if req.Method != http.MethodGet {
+			t.Error("Method for list users request is not GET")
+		}
+
+		if req.URL.String() != c.Expect {
+			t.Errorf("Wrong url for list users request. Expect %v - Got %v", c.Expect, req.URL.String())
+		}
+
+		if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
+			t.Error("Wrong header for list users request")
+		}
+	}
+}
+
+func TestRequestBuilder_BuildSearchUsersRequest(t *testing.T) {
+	token := "this is a very long token"
+	active := false
+	filter := UserFilter{
+		Roles:          []string{RolePlatformWarehouse, RoleAdmin},
+		PlatformNames:  []string{"OMS_VN"},
+		Active:         &active,
+		UsernamePrefix: "john",
+		EmailPrefix:    "john@",
+		UpdatedSince:   time.Date(2017, 8, 14, 18, 19, 3, 0, time.UTC),
+	}
+
+	req, err := builder.BuildSearchUsersRequest(context.Background(), token, filter, ListUsersParams{Page: 3, PerPage: 20})
 
 	if err != nil {
-		t.Error("Has error when creating get all request!")
+		t.Fatal("Has error when creating search users request!")
 	}
 
-	if req == nil {
-		t.Error("Get all request is nil")
+	if req.Method != http.MethodGet {
+		t.Error("Method for search users request is not GET")
+	}
+
+	expected := url.Values{
+		"role":            {RolePlatformWarehouse, RoleAdmin},
+		"platform":        {"OMS_VN"},
+		"active":          {"false"},
+		"username_prefix": {"john"},
+		"email_prefix":    {"john@"},
+		"updated_since":   {"2017-08-14T18:19:03Z"},
+		"page":            {"3"},
+		"per_page":        {"20"},
+	}
+	if !reflect.DeepEqual(req.URL.Query(), expected) {
+		t.Errorf("Wrong query for search users request. Expect %v - Got %v", expected, req.URL.Query())
+	}
+
+	if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
+		t.Error("Wrong header for search users request")
+	}
+}
+
+func TestRequestBuilder_BuildFindByIdsRequest(t *testing.T) {
+	token := "this is a very long token"
+
+	req, err := builder.BuildFindByIdsRequest(context.Background(), token, []string{"1", "2"})
+
+	if err != nil {
+		t.Fatal("Has error when creating find by ids request!")
 	}
 
 	if req.Method != http.MethodGet {
-		t.Error("Method for get all request is not GET")
+		t.Error("Method for find by ids request is not GET")
+	}
+
+	expected := url.Values{"id": {"1", "2"}, "per_page": {"2"}}
+	if !reflect.DeepEqual(req.URL.Query(), expected) {
+		t.Errorf("Wrong query for find by ids request. Expect %v - Got %v", expected, req.URL.Query())
 	}
 
 	if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
-		t.Error("Wrong header for get all request")
+		t.Error("Wrong header for find by ids request")
 	}
 }
 
 func TestRequestBuilder_BuildRevokedTokensRequest(t *testing.T) {
 	token := "this is a very long token"
 
-	req, err := builder.BuildRevokedTokensRequest(token)
+	req, err := builder.BuildRevokedTokensRequest(context.Background(), token)
 
 	if err != nil {
 		t.Error("Has error when creating Revoked tokens request!")
@@ -156,3 +236,96 @@ func TestRequestBuilder_BuildRevokedTokensRequest(t *testing.T) {
 		t.Error("Wrong header for Revoked tokens request")
 	}
 }
+
+func TestRequestBuilder_BuildWithContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	req, err := builder.BuildMeRequest(ctx, "token")
+	if err != nil {
+		t.Fatal("Has error when creating me request!")
+	}
+
+	if req.Context().Value(key{}) != "value" {
+		t.Error("Request isn't bound to the caller's context")
+	}
+}
+
+func TestRequestBuilder_BuildUserAdministrationRequests(t *testing.T) {
+	token := "this is a very long token"
+	ctx := context.Background()
+	password := "secret"
+
+	cases := []struct {
+		Name   string
+		Build  func() (*http.Request, error)
+		Method string
+		Path   string
+		Body   string
+	}{
+		{
+			"create",
+			func() (*http.Request, error) {
+				return builder.BuildCreateUserRequest(ctx, token, CreateUserInput{Username: "john", Email: "john@example.com"})
+			},
+			http.MethodPost, "/users",
+			`{"username":"john","email":"john@example.com","active":false}`,
+		},
+		{
+			"update",
+			func() (*http.Request, error) {
+				return builder.BuildUpdateUserRequest(ctx, token, "id", UpdateUserInput{Username: "john", Active: true, Roles: []string{RoleAdmin}})
+			},
+			http.MethodPut, "/users/id",
+			`{"username":"john","email":"","active":true,"roles":["Admin"]}`,
+		},
+		{
+			"patch",
+			func() (*http.Request, error) {
+				return builder.BuildPatchUserRequest(ctx, token, "id", PatchUserInput{Password: &password})
+			},
+			http.MethodPatch, "/users/id",
+			`{"password":"secret"}`,
+		},
+		{
+			"deactivate",
+			func() (*http.Request, error) {
+				return builder.BuildDeactivateUserRequest(ctx, token, "id")
+			},
+			http.MethodPost, "/users/id/deactivate",
+			"",
+		},
+		{
+			"delete",
+			func() (*http.Request, error) {
+				return builder.BuildDeleteUserRequest(ctx, token, "id")
+			},
+			http.MethodDelete, "/users/id",
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		req, err := c.Build()
+		if err != nil {
+			t.Fatalf("Has error when creating %s request!", c.Name)
+		}
+
+		if req.Method != c.Method {
+			t.Errorf("Method for %s request is not %s", c.Name, c.Method)
+		}
+
+		if req.URL.Path != c.Path {
+			t.Errorf("Wrong path for %s request. Expect %v - Got %v", c.Name, c.Path, req.URL.Path)
+		}
+
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != c.Body {
+			t.Errorf("Wrong body for %s request. Expect %v - Got %v", c.Name, c.Body, string(body))
+		}
+
+		if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
+			t.Errorf("Wrong header for %s request", c.Name)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package userclient
+
+import "context"
+
+// Method names passed to client decorators
+const (
+	MethodAuthenticate    = "Authenticate"
+	MethodLogin           = "Login"
+	MethodRefresh         = "Refresh"
+	MethodMe              = "Me"
+	MethodLogout          = "Logout"
+	MethodFindById        = "FindById"
+	MethodFindByIds       = "FindByIds"
+	MethodFindAll         = "FindAll"
+	MethodListUsers       = "ListUsers"
+	MethodSearchUsers     = "SearchUsers"
+	MethodRevokedTokens   = "RevokedTokens"
+	MethodCreateUser      = "CreateUser"
+	MethodUpdateUser      = "UpdateUser"
+	MethodPatchUser       = "PatchUser"
+	MethodDeactivateUser  = "DeactivateUser"
+	MethodDeleteUser      = "DeleteUser"
+	MethodGrantRoles      = "GrantRoles"
+	MethodRevokeRoles     = "RevokeRoles"
+	MethodAttachPlatforms = "AttachPlatforms"
+	MethodDetachPlatforms = "DetachPlatforms"
+
+	MethodChangePassword       = "ChangePassword"
+	MethodRequestPasswordReset = "RequestPasswordReset"
+	MethodConfirmPasswordReset = "ConfirmPasswordReset"
+)
+
+// interceptor is called around every call of the intercepted client,
+// it has to call call at most once and return its error
+type interceptor func(ctx context.Context, method string, call func(ctx context.Context) error) error
+
+// interceptedClient implements Client calling the wrapped client through the interceptor,
+// it's the base of client decorators
+type interceptedClient struct {
+	client    ContextClient
+	intercept interceptor
+}
+
+func (c *interceptedClient) Authenticate(username, password string) (string, error) {
+	return c.AuthenticateContext(context.Background(), username, password)
+}
+
+func (c *interceptedClient) AuthenticateContext(ctx context.Context, username, password string) (token string, err error) {
+	err = c.intercept(ctx, MethodAuthenticate, func(ctx context.Context) error {
+		token, err = c.client.AuthenticateContext(ctx, username, password)
+		return err
+	})
+	return token, err
+}
+
+func (c *interceptedClient) Login(ctx context.Context, username, password string) (login *LoginResponse, err error) {
+	err = c.intercept(ctx, MethodLogin, func(ctx context.Context) error {
+		login, err = c.client.Login(ctx, username, password)
+		return err
+	})
+	return login, err
+}
+
+func (c *interceptedClient) Refresh(ctx context.Context, refreshToken string) (login *LoginResponse, err error) {
+	err = c.intercept(ctx, MethodRefresh, func(ctx context.Context) error {
+		login, err = c.client.Refresh(ctx, refreshToken)
+		return err
+	})
+	return login, err
+}
+
+func (c *interceptedClient) Me(token string) (*User, error) {
+	return c.MeContext(context.Background(), token)
+}
+
+func (c *interceptedClient) MeContext(ctx context.Context, token string) (user *User, err error) {
+	err = c.intercept(ctx, MethodMe, func(ctx context.Context) error {
+		user, err = c.client.MeContext(ctx, token)
+		return err
+	})
+	return user, err
+}
+
+func (c *interceptedClient) Logout(token string) error {
+	return c.LogoutContext(context.Background(), token)
+}
+
+func (c *interceptedClient) LogoutContext(ctx context.Context, token string) error {
+	return c.intercept(ctx, MethodLogout, func(ctx context.Context) error {
+		return c.client.LogoutContext(ctx, token)
+	})
+}
+
+func (c *interceptedClient) FindById(token, userId string) (*User, error) {
+	return c.FindByIdContext(context.Background(), token, userId)
+}
+
+func (c *interceptedClient) FindByIdContext(ctx context.Context, token, userId string) (user *User, err error) {
+	err = c.intercept(ctx, MethodFindById, func(ctx context.Context) error {
+		user, err = c.client.FindByIdContext(ctx, token, userId)
+		return err
+	})
+	return user, err
+}
+
+func (c *interceptedClient) FindByIds(ctx context.Context, token string, userIds []string) (users map[string]*User, err error) {
+	err = c.intercept(ctx, MethodFindByIds, func(ctx context.Context) error {
+		users, err = c.client.FindByIds(ctx, token, userIds)
+		return err
+	})
+	return users, err
+}
+
+func (c *interceptedClient) FindAll(token string) ([]*User, error) {
+	return c.FindAllContext(context.Background(), token)
+}
+
+func (c *interceptedClient) FindAllContext(ctx context.Context, token string) (users []*User, err error) {
+	err = c.intercept(ctx, MethodFindAll, func(ctx context.Context) error {
+		users, err = c.client.FindAllContext(ctx, token)
+		return err
+	})
+	return users, err
+}
+
+func (c *interceptedClient) ListUsers(ctx context.Context, token string, params ListUsersParams) (page *UserPage, err error) {
+	err = c.intercept(ctx, MethodListUsers, func(ctx context.Context) error {
+		page, err = c.client.ListUsers(ctx, token, params)
+		return err
+	})
+	return page, err
+}
+
+func (c *interceptedClient) SearchUsers(ctx context.Context, token string, filter UserFilter, params ListUsersParams) (page *UserPage, err error) {
+	err = c.intercept(ctx, MethodSearchUsers, func(ctx context.Context) error {
+		page, err = c.client.SearchUsers(ctx, token, filter, params)
+		return err
+	})
+	return page, err
+}
+
+func (c *interceptedClient) RevokedTokens(token string) ([]RevokedToken, error) {
+	return c.RevokedTokensContext(context.Background(), token)
+}
+
+func (c *interceptedClient) RevokedTokensContext(ctx context.Context, token string) (tokens []RevokedToken, err error) {
+	err = c.intercept(ctx, MethodRevokedTokens, func(ctx context.Context) error {
+		tokens, err = c.client.RevokedTokensContext(ctx, token)
+		return err
+	})
+	return tokens, err
+}
+
+func (c *interceptedClient) CreateUser(ctx context.Context, token string, input CreateUserInput) (user *User, err error) {
+	err = c.intercept(ctx, MethodCreateUser, func(ctx context.Context) error {
+		user, err = c.client.CreateUser(ctx, token, input)
+		return err
+	})
+	return user, err
+}
+
+func (c *interceptedClient) UpdateUser(ctx context.Context, token, userId string, input UpdateUserInput) (user *User, err error) {
+	err = c.intercept(ctx, MethodUpdateUser, func(ctx context.Context) error {
+		user, err = c.client.UpdateUser(ctx, token, userId, input)
+		return err
+	})
+	return user, err
+}
+
+func (c *interceptedClient) PatchUser(ctx context.Context, token, userId string, input PatchUserInput) (user *User, err error) {
+	err = c.intercept(ctx, MethodPatchUser, func(ctx context.Context) error {
+		user, err = c.client.PatchUser(ctx, token, userId, input)
+		return err
+	})
+	return user, err
+}
+
+func (c *interceptedClient) DeactivateUser(ctx context.Context, token, userId string) error {
+	return c.intercept(ctx, MethodDeactivateUser, func(ctx context.Context) error {
+		return c.client.DeactivateUser(ctx, token, userId)
+	})
+}
+
+func (c *interceptedClient) DeleteUser(ctx context.Context, token, userId string) error {
+	return c.intercept(ctx, MethodDeleteUser, func(ctx context.Context) error {
+		return c.client.DeleteUser(ctx, token, userId)
+	})
+}
+
+func (c *interceptedClient) GrantRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.intercept(ctx, MethodGrantRoles, func(ctx context.Context) error {
+		return c.client.GrantRoles(ctx, token, userId, roles...)
+	})
+}
+
+func (c *interceptedClient) RevokeRoles(ctx context.Context, token, userId string, roles ...string) error {
+	return c.intercept(ctx, MethodRevokeRoles, func(ctx context.Context) error {
+		return c.client.RevokeRoles(ctx, token, userId, roles...)
+	})
+}
+
+func (c *interceptedClient) AttachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.intercept(ctx, MethodAttachPlatforms, func(ctx context.Context) error {
+		return c.client.AttachPlatforms(ctx, token, userId, platformNames...)
+	})
+}
+
+func (c *interceptedClient) DetachPlatforms(ctx context.Context, token, userId string, platformNames ...string) error {
+	return c.intercept(ctx, MethodDetachPlatforms, func(ctx context.Context) error {
+		return c.client.DetachPlatforms(ctx, token, userId, platformNames...)
+	})
+}
+
+func (c *interceptedClient) ChangePassword(ctx context.Context, token string, input ChangePasswordInput) error {
+	return c.intercept(ctx, MethodChangePassword, func(ctx context.Context) error {
+		return c.client.ChangePassword(ctx, token, input)
+	})
+}
+
+func (c *interceptedClient) RequestPasswordReset(ctx context.Context, input PasswordResetInput) error {
+	return c.intercept(ctx, MethodRequestPasswordReset, func(ctx context.Context) error {
+		return c.client.RequestPasswordReset(ctx, input)
+	})
+}
+
+func (c *interceptedClient) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	return c.intercept(ctx, MethodConfirmPasswordReset, func(ctx context.Context) error {
+		return c.client.ConfirmPasswordReset(ctx, input)
+	})
+}
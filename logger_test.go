@@ -0,0 +1,157 @@
+package userclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields Fields
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Debug(msg string, fields Fields) { l.add("debug", msg, fields) }
+func (l *recordingLogger) Info(msg string, fields Fields)  { l.add("info", msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields Fields)  { l.add("warn", msg, fields) }
+func (l *recordingLogger) Error(msg string, fields Fields) { l.add("error", msg, fields) }
+
+func (l *recordingLogger) add(level, msg string, fields Fields) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+}
+
+func TestTokenFingerprint(t *testing.T) {
+	if TokenFingerprint("") != "" {
+		t.Error("fingerprint of empty token should be empty")
+	}
+	fingerprint := TokenFingerprint("secret-token")
+	if fingerprint == "" || strings.Contains(fingerprint, "secret") {
+		t.Errorf("Wrong fingerprint %q", fingerprint)
+	}
+	if fingerprint != TokenFingerprint("secret-token") || fingerprint == TokenFingerprint("other-token") {
+		t.Error("fingerprint should identify the token")
+	}
+}
+
+func TestLogrusLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := logrus.New()
+	l.Out = buf
+	l.Formatter = &logrus.JSONFormatter{}
+
+	NewLogrusLogger(l).Warn("request failed", Fields{FieldAttempt: 2})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if entry["level"] != "warning" || entry["msg"] != "request failed" || entry[FieldAttempt] != float64(2) {
+		t.Errorf("Wrong entry %v", entry)
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil)))
+
+	l.Debug("skipped", nil)
+	l.Error("request failed", Fields{FieldStatus: 503})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if entry["level"] != "ERROR" || entry["msg"] != "request failed" || entry[FieldStatus] != float64(503) {
+		t.Errorf("Wrong entry %v", entry)
+	}
+}
+
+func TestHttpClient_Logger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	client, _ := NewClient(ts.URL, WithLogger(l), WithRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 2}))
+	client.DeleteUser(context.Background(), "secret-token", "1")
+
+	if len(l.entries) != 2 || l.entries[0].level != "warn" || l.entries[1].level != "error" {
+		t.Fatalf("Wrong log entries %v", l.entries)
+	}
+	fields := l.entries[1].fields
+	if fields[FieldAttempt] != 2 || fields[FieldStatus] != http.StatusServiceUnavailable || fields[FieldMethod] != http.MethodDelete {
+		t.Errorf("Wrong fields %v", fields)
+	}
+	if fields[FieldToken] != TokenFingerprint("secret-token") {
+		t.Errorf("Wrong token fingerprint %v", fields[FieldToken])
+	}
+}
+
+func TestAuth_Logger(t *testing.T) {
+	l := &recordingLogger{}
+	m := NewMiddleware(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return nil, errors.New("not found user")
+		},
+	}, DefaultRetryConfig)
+	m.SetStructuredLogger(l)
+
+	r := httptest.NewRequest(http.MethodGet, "/?token=secret-token", nil)
+	m.Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(httptest.NewRecorder(), r)
+
+	if len(l.entries) != 1 || l.entries[0].level != "warn" {
+		t.Fatalf("Wrong log entries %v", l.entries)
+	}
+	for _, value := range l.entries[0].fields {
+		if s, ok := value.(string); ok && strings.Contains(s, "secret-token") {
+			t.Errorf("raw token is logged: %v", l.entries[0].fields)
+		}
+	}
+}
+
+type errorRecorder struct {
+	errors [][]interface{}
+}
+
+func (r *errorRecorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, args)
+}
+
+func TestAuth_SetLogger(t *testing.T) {
+	m := NewMiddleware(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return nil, errors.New("not found user")
+		},
+	}, DefaultRetryConfig)
+	m.SetLogger(logrus.StandardLogger())
+	m.SetLogger(logrus.NewEntry(logrus.StandardLogger()))
+
+	l := &errorRecorder{}
+	m.SetLogger(l)
+
+	r := httptest.NewRequest(http.MethodGet, "/?token=secret-token", nil)
+	m.Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(httptest.NewRecorder(), r)
+
+	if len(l.errors) != 1 || l.errors[0][0] != "authentication failed" {
+		t.Errorf("Wrong errors logged %v", l.errors)
+	}
+}
@@ -0,0 +1,94 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Metrics receives measurements of client calls,
+// implementations must be safe for concurrent use
+type Metrics interface {
+	// ObserveCall is called after every client call,
+	// errorClass is empty for successful calls, see ErrorClass
+	ObserveCall(method string, duration time.Duration, errorClass string)
+	// ObserveCache is called after every cache lookup of CacheClient
+	ObserveCache(method string, hit bool)
+}
+
+// Error classes returned by ErrorClass
+const (
+	ErrorClassCanceled     = "canceled"
+	ErrorClassTimeout      = "timeout"
+	ErrorClassCircuitOpen  = "circuit_open"
+	ErrorClassUnavailable  = "unavailable"
+	ErrorClassRateLimited  = "rate_limited"
+	ErrorClassUnauthorized = "unauthorized"
+	ErrorClassForbidden    = "forbidden"
+	ErrorClassNotFound     = "not_found"
+	ErrorClassConflict     = "conflict"
+	ErrorClassValidation   = "validation"
+	ErrorClassNetwork      = "network"
+	ErrorClassOther        = "other"
+)
+
+// ErrorClass returns low cardinality class of the error suitable for metric labels,
+// empty string for nil
+func ErrorClass(err error) string {
+	var apiErr *APIError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.Is(err, ErrRateLimited):
+		return ErrorClassRateLimited
+	case errors.Is(err, ErrServiceUnavailable):
+		return ErrorClassUnavailable
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case errors.Is(err, ErrUnauthorized):
+		return ErrorClassUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return ErrorClassForbidden
+	case errors.Is(err, ErrNotFound):
+		return ErrorClassNotFound
+	case errors.Is(err, ErrConflict):
+		return ErrorClassConflict
+	case errors.Is(err, ErrValidation):
+		return ErrorClassValidation
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+	return ErrorClassOther
+}
+
+// MetricsClient reports every call of the wrapped client to Metrics
+type MetricsClient struct {
+	*interceptedClient
+
+	metrics Metrics
+}
+
+func NewMetricsClient(c ContextClient, m Metrics) *MetricsClient {
+	client := &MetricsClient{metrics: m}
+	client.interceptedClient = &interceptedClient{client: c, intercept: client.intercept}
+	return client
+}
+
+func (c *MetricsClient) intercept(ctx context.Context, method string, call func(ctx context.Context) error) error {
+	start := time.Now()
+	err := call(ctx)
+	c.metrics.ObserveCall(method, time.Since(start), ErrorClass(err))
+	return err
+}
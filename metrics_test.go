@@ -0,0 +1,88 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type metricsMock struct {
+	mu     sync.Mutex
+	calls  map[string][]string
+	hits   map[string]int
+	misses map[string]int
+}
+
+func newMetricsMock() *metricsMock {
+	return &metricsMock{
+		calls:  map[string][]string{},
+		hits:   map[string]int{},
+		misses: map[string]int{},
+	}
+}
+
+func (m *metricsMock) ObserveCall(method string, duration time.Duration, errorClass string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[method] = append(m.calls[method], errorClass)
+}
+
+func (m *metricsMock) ObserveCache(method string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.hits[method]++
+	} else {
+		m.misses[method]++
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	cases := map[string]error{
+		"":                     nil,
+		ErrorClassCanceled:     context.Canceled,
+		ErrorClassTimeout:      fmt.Errorf("get: %w", context.DeadlineExceeded),
+		ErrorClassCircuitOpen:  ErrCircuitOpen,
+		ErrorClassUnavailable:  &APIError{StatusCode: http.StatusBadGateway, err: ErrServiceUnavailable},
+		ErrorClassRateLimited:  &APIError{StatusCode: http.StatusTooManyRequests, err: errors.New("Too Many Requests")},
+		ErrorClassUnauthorized: ErrUnauthorized,
+		ErrorClassForbidden:    ErrForbidden,
+		ErrorClassNotFound:     &APIError{StatusCode: http.StatusNotFound, err: ErrNotFound},
+		ErrorClassConflict:     ErrConflict,
+		ErrorClassValidation:   ErrValidation,
+		ErrorClassNetwork:      &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+		ErrorClassOther:        errors.New("unexpected"),
+	}
+	for class, err := range cases {
+		if got := ErrorClass(err); got != class {
+			t.Errorf("Wrong class %q of error '%v', expected %q", got, err, class)
+		}
+	}
+}
+
+func TestMetricsClient(t *testing.T) {
+	metrics := newMetricsMock()
+	client := NewMetricsClient(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return &User{Id: "1"}, nil
+		},
+		FindByIdMock: func(token, userId string) (*User, error) {
+			return nil, ErrNotFound
+		},
+	}, metrics)
+
+	client.Me("token")
+	client.FindById("token", "2")
+
+	if classes := metrics.calls[MethodMe]; len(classes) != 1 || classes[0] != "" {
+		t.Errorf("Wrong Me metrics %v", classes)
+	}
+	if classes := metrics.calls[MethodFindById]; len(classes) != 1 || classes[0] != ErrorClassNotFound {
+		t.Errorf("Wrong FindById metrics %v", classes)
+	}
+}
@@ -1,11 +1,14 @@
 package userclient
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const defaultMaxAttempt = 3
@@ -15,7 +18,8 @@ const defaultWaitTime = 1000
 type Middleware struct {
 	userServiceClient Client
 	config            RetryConfig
-	logger            logger
+	logger            Logger
+	tp                trace.TracerProvider
 }
 
 type RetryConfig struct {
@@ -28,22 +32,60 @@ var DefaultRetryConfig = RetryConfig{
 	WaitTime:   defaultWaitTime,
 }
 
-type logger interface {
-	Error(...interface{})
-}
-
 func NewMiddleware(userServiceClient Client, config RetryConfig) *Middleware {
 	return &Middleware{
 		userServiceClient: userServiceClient,
 		config:            config,
-		logger:            logrus.StandardLogger(),
+		logger:            NewLogrusLogger(logrus.StandardLogger()),
 	}
 }
 
+type logger interface {
+	Error(...interface{})
+}
+
+// SetLogger sets logger of failed authentications, logrus standard logger is used by default.
+// Logrus loggers get structured entries, other loggers get only failures, see SetStructuredLogger
 func (m *Middleware) SetLogger(l logger) {
+	switch l := l.(type) {
+	case nil:
+		m.logger = NopLogger
+	case logrus.FieldLogger:
+		m.logger = NewLogrusLogger(l)
+	default:
+		m.logger = errorLogger{l}
+	}
+}
+
+// SetStructuredLogger sets logger of authentications, nil disables logging
+func (m *Middleware) SetStructuredLogger(l Logger) {
+	if l == nil {
+		l = NopLogger
+	}
 	m.logger = l
 }
 
+// errorLogger adapts logger accepting only errors, debug and info entries are dropped
+type errorLogger struct {
+	logger logger
+}
+
+func (l errorLogger) Debug(string, Fields) {}
+func (l errorLogger) Info(string, Fields)  {}
+
+func (l errorLogger) Warn(msg string, fields Fields) {
+	l.logger.Error(msg, fields)
+}
+
+func (l errorLogger) Error(msg string, fields Fields) {
+	l.logger.Error(msg, fields)
+}
+
+// SetTracerProvider sets provider of Auth spans, the global provider is used by default
+func (m *Middleware) SetTracerProvider(tp trace.TracerProvider) {
+	m.tp = tp
+}
+
 func (m *Middleware) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
@@ -52,35 +94,81 @@ func (m *Middleware) Auth(next http.Handler) http.Handler {
 			tokenString = r.URL.Query().Get("token")
 		}
 
+		ctx, span := tracer(m.tp).Start(r.Context(), "userclient.Middleware.Auth")
+
 		var user *User
 		var err error
-		for i := 0; i < m.config.MaxAttempt; i++ {
-			user, err = m.userServiceClient.Me(tokenString)
-			if err != ErrServiceUnavailable {
+		var attempt int
+		retryPolicy := m.config.Policy()
+		for attempt = 1; ; attempt++ {
+			user, err = m.userServiceClient.MeContext(ctx, tokenString)
+
+			delay, retry := retryPolicy.Backoff(nil, nil, err, attempt)
+			if !retry {
 				break
 			}
-
-			time.Sleep(time.Millisecond * time.Duration(m.config.WaitTime))
+			span.AddEvent("retry", trace.WithAttributes(AttributeAttempt.Int(attempt+1)))
+			if !sleepContext(ctx, delay) {
+				break
+			}
+		}
+		if err == nil && user != nil {
+			span.SetAttributes(AttributeUserId.String(user.Id))
+			trace.SpanFromContext(r.Context()).SetAttributes(AttributeUserId.String(user.Id))
 		}
+		endSpan(span, err)
 
+		fields := Fields{
+			FieldPath:    r.URL.Path,
+			FieldAttempt: attempt,
+			FieldToken:   TokenFingerprint(tokenString),
+		}
 		if err != nil {
-			m.logger.Error(err)
-			http.Error(w, err.Error(), http.StatusUnauthorized)
+			fields[FieldError] = err.Error()
+			code := http.StatusUnauthorized
+			if errors.Is(err, ErrForbidden) {
+				code = http.StatusForbidden
+			}
+			if IsRetryable(err) {
+				m.logger.Error("authentication failed", fields)
+			} else {
+				m.logger.Warn("authentication failed", fields)
+			}
+			// the error is logged, it may reveal details of user service
+			http.Error(w, http.StatusText(code), code)
 			return
 		}
 
 		if user == nil {
+			m.logger.Warn("authentication failed: no user returned", fields)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
+		fields[FieldUserId] = user.Id
+		m.logger.Debug("user authenticated", fields)
 
-		ctx := ContextWithUser(r.Context(), user)
+		// the handler isn't a child of the finished Auth span
+		ctx = ContextWithUser(r.Context(), user)
 		ctx = ContextWithToken(ctx, tokenString)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// sleepContext pauses for d or until ctx is done,
+// it returns false if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func CheckRoles(roles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
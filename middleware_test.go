@@ -1,6 +1,7 @@
 package userclient
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -21,7 +22,7 @@ var authCases = []struct {
 			},
 		},
 		401,
-		"not found user",
+		"Unauthorized",
 	},
 	{
 		UserClientMock{
@@ -39,7 +40,7 @@ var authCases = []struct {
 			},
 		},
 		401,
-		"service is unavailable",
+		"Unauthorized",
 	},
 	{
 		UserClientMock{
@@ -50,6 +51,15 @@ var authCases = []struct {
 		200,
 		"ok",
 	},
+	{
+		UserClientMock{
+			MeMock: func(token string) (*User, error) {
+				return nil, &APIError{StatusCode: 401, Method: "GET", Path: "/users/me", Message: "token signature invalid", err: ErrUnauthorized}
+			},
+		},
+		401,
+		"Unauthorized",
+	},
 }
 
 var aclCases = []struct {
@@ -219,3 +229,78 @@ func TestGetCurrentUserFromContext(t *testing.T) {
 		t.Errorf("Wrong user name. Expect %v - Got %v", "test@lazada.com", user.Email)
 	}
 }
+
+func TestAuthPassesRequestContext(t *testing.T) {
+	type key struct{}
+
+	var received context.Context
+	clientMock := UserClientMock{
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			received = ctx
+			return &User{}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://localhost/test", nil)
+	r = r.WithContext(context.WithValue(r.Context(), key{}, "value"))
+	r.Header.Set("Authorization", "this is token string")
+
+	middleware := NewMiddleware(&clientMock, DefaultRetryConfig)
+	middleware.Auth(testHandler).ServeHTTP(w, r)
+
+	if received == nil || received.Value(key{}) != "value" {
+		t.Error("Request context should be passed to the client")
+	}
+}
+
+func TestAuthStopsRetryingWhenContextIsDone(t *testing.T) {
+	calls := 0
+	clientMock := UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			calls++
+			return nil, ErrServiceUnavailable
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://localhost/test", nil).WithContext(ctx)
+	r.Header.Set("Authorization", "this is token string")
+
+	middleware := NewMiddleware(&clientMock, DefaultRetryConfig)
+	middleware.Auth(testHandler).ServeHTTP(w, r)
+
+	if calls != 1 {
+		t.Errorf("Client should be called once, but it called %d", calls)
+	}
+	if w.Result().StatusCode != http.StatusUnauthorized {
+		t.Errorf("Wrong response code. Expect %v - Got %v", http.StatusUnauthorized, w.Result().StatusCode)
+	}
+}
+
+func TestAuthForbiddenIsNotRetried(t *testing.T) {
+	calls := 0
+	clientMock := UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			calls++
+			return nil, ErrForbidden
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://localhost/test", nil)
+	r.Header.Set("Authorization", "this is token string")
+
+	middleware := NewMiddleware(&clientMock, DefaultRetryConfig)
+	middleware.Auth(testHandler).ServeHTTP(w, r)
+
+	if calls != 1 {
+		t.Errorf("Client should be called once, but it called %d", calls)
+	}
+	if w.Result().StatusCode != http.StatusForbidden {
+		t.Errorf("Wrong response code. Expect %v - Got %v", http.StatusForbidden, w.Result().StatusCode)
+	}
+}
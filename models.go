@@ -80,6 +80,128 @@ type User struct {
 	PlatformNames []string  `json:"platforms"`
 }
 
+// LoginResponse is returned by login and token refresh,
+// RefreshToken and ExpiresAt are empty when user service doesn't provide them
+type LoginResponse struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// Expired reports whether the access token expires within the leeway,
+// tokens without expiry never expire
+func (r *LoginResponse) Expired(leeway time.Duration) bool {
+	return !r.ExpiresAt.IsZero() && !time.Now().Add(leeway).Before(r.ExpiresAt)
+}
+
+// CreateUserInput is the payload of user creation,
+// Password is sent only when set
+type CreateUserInput struct {
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Password string   `json:"password,omitempty"`
+	Active   bool     `json:"active"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+// UpdateUserInput replaces user attributes,
+// Password is sent only when set
+type UpdateUserInput struct {
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Password string   `json:"password,omitempty"`
+	Active   bool     `json:"active"`
+	Roles    []string `json:"roles"`
+}
+
+// PatchUserInput changes only attributes which are set
+type PatchUserInput struct {
+	Username *string `json:"username,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Password *string `json:"password,omitempty"`
+	Active   *bool   `json:"active,omitempty"`
+}
+
+// ChangePasswordInput is the payload of password change
+type ChangePasswordInput struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+// PasswordResetInput identifies the user by username or email
+type PasswordResetInput struct {
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// ConfirmPasswordResetInput sets new password with the token sent to the user
+type ConfirmPasswordResetInput struct {
+	ResetToken  string `json:"token"`
+	NewPassword string `json:"password"`
+}
+
+// ListUsersParams selects a page of users,
+// Cursor takes precedence over Page when both are set
+type ListUsersParams struct {
+	Page    int
+	PerPage int
+	Cursor  string
+}
+
+// UserFilter narrows down users returned by search,
+// zero value fields are ignored
+type UserFilter struct {
+	// Roles matches users having any of the roles
+	Roles []string
+	// PlatformNames matches users having access to any of the platforms
+	PlatformNames  []string
+	Active         *bool
+	UsernamePrefix string
+	EmailPrefix    string
+	UpdatedSince   time.Time
+}
+
+// UserPage is a single page of users
+type UserPage struct {
+	Users      []*User
+	Page       int
+	PerPage    int
+	Total      int
+	NextCursor string
+}
+
+// next returns params of the page following p,
+// false is returned if p is the last page.
+// Without total and cursor paging stops at an empty page, as the server may cap the page size
+func (p *UserPage) next(params ListUsersParams) (ListUsersParams, bool) {
+	if p.NextCursor != "" {
+		params.Cursor = p.NextCursor
+		return params, true
+	}
+	if params.Cursor != "" || len(p.Users) == 0 {
+		return params, false
+	}
+
+	page := p.Page
+	if page == 0 {
+		page = params.Page
+	}
+	if page == 0 {
+		page = 1
+	}
+
+	params.Page = page + 1
+	if p.Total > 0 {
+		perPage := p.PerPage
+		if perPage == 0 {
+			// the requested size may be capped
+			perPage = len(p.Users)
+		}
+		return params, page*perPage < p.Total
+	}
+	return params, true
+}
+
 type RevokedToken struct {
 	Token     string    `json:"token"`
 	ExpiredAt time.Time `json:"expiredAt"`
@@ -119,3 +241,35 @@ func (u *User) HasPlatformRole() bool {
 	}
 	return false
 }
+
+// clone returns deep copy of the user
+func (u *User) clone() *User {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	c.Roles = cloneStrings(u.Roles)
+	c.PlatformNames = cloneStrings(u.PlatformNames)
+	return &c
+}
+
+// clone returns deep copy of the page
+func (p *UserPage) clone() *UserPage {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	c.Users = make([]*User, len(p.Users))
+	for i, u := range p.Users {
+		c.Users[i] = u.clone()
+	}
+	return &c
+}
+
+// cloneStrings copies the slice keeping nil and empty slices apart
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
@@ -0,0 +1,161 @@
+package userclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures HttpClient created by NewClient
+type Option func(c *HttpClient) error
+
+// MetricsHook is called after every HTTP attempt,
+// resp is nil on network errors and its body must not be read
+type MetricsHook func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// TracingHook is called before every HTTP attempt, attempt starts from 1.
+// It returns the request to send, e.g. with span context and propagation headers,
+// and the function called when the attempt is finished.
+type TracingHook func(req *http.Request, attempt int) (*http.Request, func(resp *http.Response, err error))
+
+// NewClient returns HttpClient for user service at baseURL,
+// by default it has 10 seconds timeout and no retries
+func NewClient(baseURL string, opts ...Option) (*HttpClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url %q should be absolute", baseURL)
+	}
+
+	c := NewDefault(baseURL)
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// WithTimeout sets timeout of a single HTTP attempt, zero means no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HttpClient) error {
+		if timeout < 0 {
+			return errors.New("timeout should not be negative")
+		}
+		c.requestClient.Timeout = timeout
+		return nil
+	}
+}
+
+// WithTransport sets transport of the underlying http.Client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *HttpClient) error {
+		c.requestClient.Transport = transport
+		return nil
+	}
+}
+
+// WithUserAgent sets User-Agent header of all requests
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader adds header to all requests
+// unless the request already has it
+func WithHeader(key, value string) Option {
+	return func(c *HttpClient) error {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// WithDefaultHeaders adds headers to all requests
+// unless the request already has them
+func WithDefaultHeaders(headers http.Header) Option {
+	return func(c *HttpClient) error {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		for key, values := range headers {
+			for _, value := range values {
+				c.headers.Add(key, value)
+			}
+		}
+		return nil
+	}
+}
+
+// WithRetryPolicy enables retries of failed requests
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *HttpClient) error {
+		c.SetRetryPolicy(p)
+		return nil
+	}
+}
+
+// WithLogger sets logger of HTTP attempts
+func WithLogger(l Logger) Option {
+	return func(c *HttpClient) error {
+		c.SetLogger(l)
+		return nil
+	}
+}
+
+// WithMetricsHook sets hook called after every HTTP attempt
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(c *HttpClient) error {
+		c.metricsHook = hook
+		return nil
+	}
+}
+
+// WithTracingHook sets hook called around every HTTP attempt
+func WithTracingHook(hook TracingHook) Option {
+	return func(c *HttpClient) error {
+		c.tracingHook = hook
+		return nil
+	}
+}
+
+// WithEmbeddedPlatforms requests users with embedded platforms (include=platforms)
+// instead of fetching platforms with another request,
+// the request is still made if user service doesn't embed them
+func WithEmbeddedPlatforms() Option {
+	return func(c *HttpClient) error {
+		c.embedPlatforms = true
+		return nil
+	}
+}
+
+// WithStrictPlatforms makes Me and FindById fail if platforms of the user can't be fetched
+// instead of returning the user without platforms
+func WithStrictPlatforms() Option {
+	return func(c *HttpClient) error {
+		c.strictPlatforms = true
+		return nil
+	}
+}
+
+// defaultEnrichmentConcurrency is the number of parallel platform requests if not set
+const defaultEnrichmentConcurrency = 8
+
+// WithPlatformEnrichment fetches platforms of users returned by FindAll, FindByIds,
+// ListUsers and SearchUsers with up to concurrency parallel requests, 8 if it's not positive.
+// If platforms of some users can't be fetched, the users are returned along with PlatformsError.
+func WithPlatformEnrichment(concurrency int) Option {
+	return func(c *HttpClient) error {
+		if concurrency <= 0 {
+			concurrency = defaultEnrichmentConcurrency
+		}
+		c.enrichmentConcurrency = concurrency
+		return nil
+	}
+}
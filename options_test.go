@@ -0,0 +1,130 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClient_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "/users", "localhost:8080", "http://%zz"} {
+		if _, err := NewClient(baseURL); err == nil {
+			t.Errorf("error should be returned for base url %q", baseURL)
+		}
+	}
+}
+
+func TestNewClient_Options(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("User-Agent") != "orders/1.0" {
+				t.Errorf("Wrong user agent %q", r.Header.Get("User-Agent"))
+			}
+			if r.Header.Get("X-Tenant") != "vn" {
+				t.Errorf("Wrong tenant header %q", r.Header.Get("X-Tenant"))
+			}
+			if r.Header.Get("X-Source") != "orders" {
+				t.Errorf("Wrong source header %q", r.Header.Get("X-Source"))
+			}
+			w.Write([]byte(`{"data": {"id": "1"}}`))
+		}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL,
+		WithTimeout(time.Second),
+		WithUserAgent("orders/1.0"),
+		WithHeader("X-Tenant", "vn"),
+		WithDefaultHeaders(http.Header{"X-Source": {"orders"}}),
+	)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if client.requestClient.Timeout != time.Second {
+		t.Errorf("Wrong timeout %v", client.requestClient.Timeout)
+	}
+
+	user, err := client.CreateUser(context.Background(), "token", CreateUserInput{})
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if user.Id != "1" {
+		t.Errorf("Wrong user returned: %+v", user)
+	}
+}
+
+func TestNewClient_InvalidOption(t *testing.T) {
+	if _, err := NewClient("http://localhost", WithTimeout(-time.Second)); err == nil {
+		t.Error("error should be returned for negative timeout")
+	}
+}
+
+func TestNewClient_Hooks(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Header.Get("X-Attempt") == "" {
+				t.Error("Tracing hook should modify request")
+			}
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}))
+	defer ts.Close()
+
+	var statuses []int
+	var attempts []int
+	finished := 0
+	client, err := NewClient(ts.URL,
+		WithRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 2}),
+		WithMetricsHook(func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+			statuses = append(statuses, resp.StatusCode)
+		}),
+		WithTracingHook(func(req *http.Request, attempt int) (*http.Request, func(*http.Response, error)) {
+			attempts = append(attempts, attempt)
+			req.Header.Set("X-Attempt", "1")
+			return req, func(*http.Response, error) {
+				finished++
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	if err := client.DeleteUser(context.Background(), "token", "id"); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if len(statuses) != 2 || statuses[0] != http.StatusServiceUnavailable || statuses[1] != http.StatusOK {
+		t.Errorf("Metrics hook should be called per attempt, got %v", statuses)
+	}
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 || finished != 2 {
+		t.Errorf("Tracing hook should be called per attempt, got %v, finished %d", attempts, finished)
+	}
+}
+
+func TestNewClient_HeadersNotShared(t *testing.T) {
+	var sources []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sources = append(sources, r.Header.Get("X-Source"))
+		}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, WithHeader("X-Source", "orders"), WithBeforeRequest(func(req *http.Request) error {
+		req.Header["X-Source"][0] += "!"
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	client.DeleteUser(context.Background(), "token", "1")
+	client.DeleteUser(context.Background(), "token", "1")
+	if len(sources) != 2 || sources[1] != "orders!" {
+		t.Errorf("Default headers shouldn't be changed by requests, got %v", sources)
+	}
+}
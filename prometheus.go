@@ -0,0 +1,101 @@
+package userclient
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics implements Metrics with Prometheus collectors:
+//
+//	user_client_requests_total{method}
+//	user_client_errors_total{method, class}
+//	user_client_request_duration_seconds{method}
+//	user_client_cache_requests_total{method, result="hit|miss"}
+//	user_client_hedged_requests_total{method, winner="primary|hedge"}
+type PrometheusMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	cache    *prometheus.CounterVec
+	hedges   *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates collectors prefixed with the namespace
+// and registers them in reg, prometheus.DefaultRegisterer is used if reg is nil
+func NewPrometheusMetrics(namespace string, reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	m := &PrometheusMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "requests_total",
+			Help:      "Number of user service client calls.",
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "errors_total",
+			Help:      "Number of failed user service client calls by error class.",
+		}, []string{"method", "class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of user service client calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "cache_requests_total",
+			Help:      "Number of user cache lookups by result.",
+		}, []string{"method", "result"}),
+		hedges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "user_client",
+			Name:      "hedged_requests_total",
+			Help:      "Number of hedged user service client calls by the request answered first.",
+		}, []string{"method", "winner"}),
+	}
+
+	collectors := []prometheus.Collector{m.requests, m.errors, m.duration, m.cache, m.hedges}
+	for i, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			// registration may be retried with the same registry
+			for _, registered := range collectors[:i] {
+				reg.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) ObserveCall(method string, duration time.Duration, errorClass string) {
+	m.requests.WithLabelValues(method).Inc()
+	m.duration.WithLabelValues(method).Observe(duration.Seconds())
+	if errorClass != "" {
+		m.errors.WithLabelValues(method, errorClass).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) ObserveCache(method string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cache.WithLabelValues(method, result).Inc()
+}
+
+func (m *PrometheusMetrics) ObserveHedge(method string, hedgeWon bool) {
+	winner := "primary"
+	if hedgeWon {
+		winner = "hedge"
+	}
+	m.hedges.WithLabelValues(method, winner).Inc()
+}
@@ -0,0 +1,70 @@
+package userclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics("orders", reg)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	m.ObserveCall(MethodMe, time.Millisecond, "")
+	m.ObserveCall(MethodMe, time.Millisecond, ErrorClassUnavailable)
+	m.ObserveCache(MethodMe, true)
+	m.ObserveCache(MethodMe, false)
+	m.ObserveCache(MethodMe, true)
+
+	if v := testutil.ToFloat64(m.requests.WithLabelValues(MethodMe)); v != 2 {
+		t.Errorf("Wrong requests count %v", v)
+	}
+	if v := testutil.ToFloat64(m.errors.WithLabelValues(MethodMe, ErrorClassUnavailable)); v != 1 {
+		t.Errorf("Wrong errors count %v", v)
+	}
+	if v := testutil.ToFloat64(m.cache.WithLabelValues(MethodMe, "hit")); v != 2 {
+		t.Errorf("Wrong cache hits count %v", v)
+	}
+	m.ObserveHedge(MethodMe, true)
+	if v := testutil.ToFloat64(m.hedges.WithLabelValues(MethodMe, "hedge")); v != 1 {
+		t.Errorf("Wrong hedges count %v", v)
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 1 {
+		t.Errorf("Wrong number of histograms %d", n)
+	}
+
+	if _, err := NewPrometheusMetrics("orders", reg); err == nil {
+		t.Error("error should be returned for already registered collectors")
+	}
+}
+
+// failingRegisterer fails the nth registration once
+type failingRegisterer struct {
+	prometheus.Registerer
+	n int
+}
+
+func (r *failingRegisterer) Register(c prometheus.Collector) error {
+	r.n--
+	if r.n == 0 {
+		return errors.New("registration failed")
+	}
+	return r.Registerer.Register(c)
+}
+
+func TestPrometheusMetrics_RegisterRetry(t *testing.T) {
+	reg := &failingRegisterer{Registerer: prometheus.NewRegistry(), n: 4}
+	if _, err := NewPrometheusMetrics("orders", reg); err == nil {
+		t.Fatal("error should be returned for failed registration")
+	}
+
+	if _, err := NewPrometheusMetrics("orders", reg); err != nil {
+		t.Errorf("registration should be retried, got '%s'", err)
+	}
+}
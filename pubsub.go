@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	redis "gopkg.in/redis.v5"
@@ -25,14 +26,27 @@ type Message struct {
 
 // SubscribeRedis returns PubSub implemented on top of redis
 func SubscribeRedis(c *redis.Client) (PubSub, error) {
+	return SubscribeRedisWithLogger(c, NopLogger)
+}
+
+// SubscribeRedisWithLogger returns PubSub implemented on top of redis
+// logging connection checks and malformed messages
+func SubscribeRedisWithLogger(c *redis.Client, l Logger) (PubSub, error) {
+	if l == nil {
+		l = NopLogger
+	}
 	pubsub, err := c.Subscribe(channelName)
 	if err != nil {
+		l.Error("redis subscription failed", Fields{"channel": channelName, FieldError: err.Error()})
 		return nil, err
 	}
-	return &redisPubSub{
+	p := &redisPubSub{
 		pubsub:         pubsub,
 		receiveTimeout: time.Minute,
-	}, nil
+		logger:         l,
+	}
+	p.touch()
+	return p, nil
 }
 
 const channelName = "events-channel"
@@ -40,6 +54,19 @@ const channelName = "events-channel"
 type redisPubSub struct {
 	pubsub         *redis.PubSub
 	receiveTimeout time.Duration
+	logger         Logger
+
+	// lastActivity is unix time in nanoseconds of the last message or successful ping
+	lastActivity int64
+}
+
+// LastActivity returns time of the last received message or successful connection check
+func (c *redisPubSub) LastActivity() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.lastActivity))
+}
+
+func (c *redisPubSub) touch() {
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
 }
 
 func (c *redisPubSub) ReceiveMsg() (Message, error) {
@@ -47,15 +74,19 @@ func (c *redisPubSub) ReceiveMsg() (Message, error) {
 		msgi, err := c.pubsub.ReceiveTimeout(c.receiveTimeout)
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				c.logger.Debug("no pubsub messages received, checking connection", Fields{"channel": channelName})
 				err = c.pubsub.Ping()
 				if err != nil {
 					err = fmt.Errorf("PubSub.Ping failed: %s", err)
 				} else {
+					c.touch()
 					continue
 				}
 			}
+			c.logger.Warn("pubsub receive failed", Fields{"channel": channelName, FieldError: err.Error()})
 			return Message{}, err
 		}
+		c.touch()
 
 		switch msg := msgi.(type) {
 		case *redis.Subscription:
@@ -63,8 +94,18 @@ func (c *redisPubSub) ReceiveMsg() (Message, error) {
 		case *redis.Pong:
 			// Ignore.
 		case *redis.Message:
-			return c.parseMsg(msg)
+			m, err := c.parseMsg(msg)
+			if err != nil {
+				// the payload may contain a revoked token
+				c.logger.Warn("malformed pubsub message", Fields{
+					"channel":             msg.Channel,
+					"payload_length":      len(msg.Payload),
+					"payload_fingerprint": TokenFingerprint(msg.Payload),
+				})
+			}
+			return m, err
 		default:
+			c.logger.Warn("unknown pubsub message", Fields{"channel": channelName, "type": fmt.Sprintf("%T", msgi)})
 			return Message{}, fmt.Errorf("unknown message: %T", msgi)
 		}
 	}
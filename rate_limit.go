@@ -0,0 +1,182 @@
+package userclient
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit of calls to user service, zero values mean no limit
+type Limit struct {
+	// Rate is the number of calls per second
+	Rate float64
+	// Burst is the number of calls allowed at once, Rate rounded up if not set
+	Burst int
+	// MaxInFlight limits the number of concurrent calls
+	MaxInFlight int
+}
+
+type RateLimitConfig struct {
+	// Default is shared by all methods without their own limit
+	Default Limit
+	// Methods sets limits of particular methods, e.g. MethodFindById
+	Methods map[string]Limit
+	// FailFast makes calls exceeding the limit fail with RateLimitError instead of waiting
+	FailFast bool
+}
+
+// RateLimitedClient limits rate and concurrency of calls to user service,
+// calls exceeding the limit wait for it or fail with RateLimitError
+type RateLimitedClient struct {
+	*interceptedClient
+
+	failFast       bool
+	defaultLimiter *limiter
+	limiters       map[string]*limiter
+}
+
+func NewRateLimitedClient(c ContextClient, config RateLimitConfig) *RateLimitedClient {
+	client := &RateLimitedClient{
+		failFast:       config.FailFast,
+		defaultLimiter: newLimiter(config.Default, time.Now),
+		limiters:       make(map[string]*limiter, len(config.Methods)),
+	}
+	for method, limit := range config.Methods {
+		client.limiters[method] = newLimiter(limit, time.Now)
+	}
+	client.interceptedClient = &interceptedClient{client: c, intercept: client.intercept}
+	return client
+}
+
+func (c *RateLimitedClient) intercept(ctx context.Context, method string, call func(ctx context.Context) error) error {
+	l, ok := c.limiters[method]
+	if !ok {
+		l = c.defaultLimiter
+	}
+
+	release, err := l.acquire(ctx, method, c.failFast)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return call(ctx)
+}
+
+// limiter is a token bucket combined with a semaphore
+type limiter struct {
+	rate     float64
+	burst    float64
+	inFlight chan struct{}
+	now      func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(limit Limit, now func() time.Time) *limiter {
+	l := &limiter{rate: limit.Rate, now: now}
+	if limit.Rate > 0 {
+		l.burst = float64(limit.Burst)
+		if l.burst <= 0 {
+			l.burst = math.Ceil(limit.Rate)
+		}
+		l.tokens = l.burst
+		l.last = now()
+	}
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits for the concurrency and the rate limits,
+// release has to be called when the call is finished.
+// The slot is taken first so a refused call doesn't spend a token of the bucket
+func (l *limiter) acquire(ctx context.Context, method string, failFast bool) (release func(), err error) {
+	release, err = l.enter(ctx, method, failFast)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.wait(ctx, method, failFast); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// enter takes a slot of calls in flight
+func (l *limiter) enter(ctx context.Context, method string, failFast bool) (release func(), err error) {
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	if failFast {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			return nil, &RateLimitError{Method: method, Concurrency: true}
+		}
+	} else {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() { <-l.inFlight }, nil
+}
+
+// wait takes a token from the bucket waiting for it if needed
+func (l *limiter) wait(ctx context.Context, method string, failFast bool) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	delay, ok := l.reserve(failFast)
+	if !ok {
+		return &RateLimitError{Method: method, RetryAfter: delay}
+	}
+	if delay <= 0 {
+		return nil
+	}
+	if !sleepContext(ctx, delay) {
+		l.cancel()
+		return ctx.Err()
+	}
+	return nil
+}
+
+// reserve takes a token and returns the delay until it's available,
+// with failFast the token is taken only if it's available right away
+func (l *limiter) reserve(failFast bool) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if failFast && l.tokens < 1 {
+		return l.delay(1 - l.tokens), false
+	}
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0, true
+	}
+	return l.delay(-l.tokens), true
+}
+
+// cancel returns the reserved token to the bucket
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+func (l *limiter) delay(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
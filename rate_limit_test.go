@@ -0,0 +1,126 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedClient_FailFast(t *testing.T) {
+	client := NewRateLimitedClient(&UserClientMock{
+		FindByIdMock: func(token, userId string) (*User, error) {
+			return &User{Id: userId}, nil
+		},
+		MeMock: func(token string) (*User, error) {
+			return &User{}, nil
+		},
+	}, RateLimitConfig{
+		Methods:  map[string]Limit{MethodFindById: {Rate: 1, Burst: 2}},
+		FailFast: true,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FindById("token", "1"); err != nil {
+			t.Fatalf("error '%s' returned", err)
+		}
+	}
+
+	_, err := client.FindById("token", "1")
+	var rateErr *RateLimitError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rateErr) {
+		t.Fatalf("Wrong error '%v' returned", err)
+	}
+	if rateErr.Method != MethodFindById || rateErr.Concurrency || rateErr.RetryAfter <= 0 {
+		t.Errorf("Wrong rate limit error %+v", rateErr)
+	}
+
+	// the default limit isn't set
+	for i := 0; i < 10; i++ {
+		if _, err := client.Me("token"); err != nil {
+			t.Fatalf("error '%s' returned", err)
+		}
+	}
+}
+
+func TestRateLimitedClient_Wait(t *testing.T) {
+	client := NewRateLimitedClient(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return &User{}, nil
+		},
+	}, RateLimitConfig{Default: Limit{Rate: 100, Burst: 1}})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Me("token"); err != nil {
+			t.Fatalf("error '%s' returned", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("calls should wait for the limit, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.MeContext(context.Background(), "token")
+	if _, err := client.MeContext(ctx, "token"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+}
+
+func TestRateLimitedClient_MaxInFlight(t *testing.T) {
+	started := make(chan struct{})
+	done := make(chan struct{})
+	client := NewRateLimitedClient(&UserClientMock{
+		DeleteUserMock: func(ctx context.Context, token, userId string) error {
+			close(started)
+			<-done
+			return nil
+		},
+	}, RateLimitConfig{Default: Limit{MaxInFlight: 1}, FailFast: true})
+
+	go client.DeleteUser(context.Background(), "token", "1")
+	<-started
+
+	err := client.DeleteUser(context.Background(), "token", "2")
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || !rateErr.Concurrency {
+		t.Errorf("Wrong error '%v' returned", err)
+	}
+	close(done)
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(Limit{Rate: 2, Burst: 1}, func() time.Time { return now })
+
+	if _, ok := l.reserve(true); !ok {
+		t.Fatal("first call should be allowed")
+	}
+	if delay, ok := l.reserve(true); ok || delay != 500*time.Millisecond {
+		t.Errorf("Wrong delay %s", delay)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, ok := l.reserve(true); !ok {
+		t.Error("call should be allowed after refill")
+	}
+}
+
+func TestLimiter_RefusedCallKeepsToken(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(Limit{Rate: 1, Burst: 2, MaxInFlight: 1}, func() time.Time { return now })
+
+	release, err := l.acquire(context.Background(), MethodMe, true)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if _, err := l.acquire(context.Background(), MethodMe, true); err == nil {
+		t.Fatal("call over the concurrency limit should be refused")
+	}
+	release()
+
+	if _, err := l.acquire(context.Background(), MethodMe, true); err != nil {
+		t.Errorf("refused call shouldn't spend a token, got '%s'", err)
+	}
+}
@@ -0,0 +1,142 @@
+package userclient
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed HTTP attempt should be repeated
+type RetryPolicy interface {
+	// Backoff returns delay before the next attempt and false if the request shouldn't be repeated.
+	// attempt is the number of the failed attempt starting from 1,
+	// req and resp are nil when the policy is used outside of HttpClient.
+	Backoff(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool)
+}
+
+// BackoffRetryPolicy retries network errors and retryable status codes
+// with exponential backoff and jitter, Retry-After response header is honored
+type BackoffRetryPolicy struct {
+	// MaxAttempts includes the first attempt
+	MaxAttempts int
+	// BaseDelay is the delay after the first attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the delay, no cap if it's zero.
+	// The request isn't repeated if Retry-After asks to wait longer than MaxDelay.
+	MaxDelay time.Duration
+	// Multiplier of the delay between consecutive attempts, 2 if not set
+	Multiplier float64
+	// Jitter randomizes the delay by up to the fraction of it, e.g. 0.2 means ±20%
+	Jitter float64
+	// RetryNonIdempotent allows to repeat POST and PATCH requests
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts waiting 100ms and 200ms ±20% between them
+var DefaultRetryPolicy = &BackoffRetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.2,
+}
+
+func (p *BackoffRetryPolicy) Backoff(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !IsRetryable(err) {
+		return 0, false
+	}
+	if req != nil && !p.RetryNonIdempotent && !isIdempotent(req.Method) {
+		return 0, false
+	}
+
+	delay := p.delay(attempt)
+	if retryAfter, ok := parseRetryAfter(resp); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return 0, false
+		}
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+
+	return delay, true
+}
+
+func (p *BackoffRetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// Policy maps the config onto the retry policy with fixed delay
+func (c RetryConfig) Policy() *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts: c.MaxAttempt,
+		BaseDelay:   time.Millisecond * time.Duration(c.WaitTime),
+		Multiplier:  1,
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter supports both delay in seconds and HTTP date
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// rewindRequest returns copy of the request which can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can't be rewound")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+
+	return next, nil
+}
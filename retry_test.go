@@ -0,0 +1,160 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffRetryPolicy_Delay(t *testing.T) {
+	policy := &BackoffRetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    300 * time.Millisecond,
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, e := range expected {
+		delay, retry := policy.Backoff(nil, nil, ErrServiceUnavailable, i+1)
+		if !retry {
+			t.Errorf("attempt %d should be retried", i+1)
+		}
+		if delay != e {
+			t.Errorf("attempt %d: Expect %v - Got %v", i+1, e, delay)
+		}
+	}
+
+	if _, retry := policy.Backoff(nil, nil, ErrServiceUnavailable, 5); retry {
+		t.Error("last attempt shouldn't be retried")
+	}
+}
+
+func TestBackoffRetryPolicy_Jitter(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxAttempts: 2, BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay, _ := policy.Backoff(nil, nil, ErrServiceUnavailable, 1)
+		if delay < 50*time.Millisecond || delay > 150*time.Millisecond {
+			t.Fatalf("delay %v is out of jitter range", delay)
+		}
+	}
+}
+
+func TestBackoffRetryPolicy_NotRetryable(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxAttempts: 3}
+	post := httptest.NewRequest(http.MethodPost, "/users", nil)
+
+	cases := []struct {
+		Name string
+		Req  *http.Request
+		Err  error
+	}{
+		{"success", nil, nil},
+		{"unauthorized", nil, ErrUnauthorized},
+		{"forbidden", nil, ErrForbidden},
+		{"unknown", nil, errors.New("unknown")},
+		{"post", post, ErrServiceUnavailable},
+	}
+
+	for _, c := range cases {
+		if _, retry := policy.Backoff(c.Req, nil, c.Err, 1); retry {
+			t.Errorf("%s shouldn't be retried", c.Name)
+		}
+	}
+
+	policy.RetryNonIdempotent = true
+	if _, retry := policy.Backoff(post, nil, ErrServiceUnavailable, 1); !retry {
+		t.Error("post should be retried when non idempotent requests are allowed")
+	}
+}
+
+func TestBackoffRetryPolicy_RetryAfter(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+	delay, retry := policy.Backoff(nil, resp, ErrServiceUnavailable, 1)
+	if !retry || delay != 2*time.Second {
+		t.Errorf("Expect 2s delay - Got %v, %v", delay, retry)
+	}
+
+	resp = &http.Response{Header: http.Header{"Retry-After": {"60"}}}
+	if _, retry := policy.Backoff(nil, resp, ErrServiceUnavailable, 1); retry {
+		t.Error("shouldn't retry if Retry-After exceeds max delay")
+	}
+}
+
+func TestUserHttpClient_Retry(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"data": [{"id": "1"}]}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	client.SetRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	users, err := client.FindByIds(context.Background(), "token", []string{"1"})
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if users["1"] == nil {
+		t.Error("user should be found")
+	}
+	if calls != 3 {
+		t.Errorf("should be 3 calls, made %d", calls)
+	}
+}
+
+func TestUserHttpClient_RetryRewindsBody(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body := make([]byte, r.ContentLength)
+			r.Body.Read(body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{"token": "token"}`))
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	client.SetRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 2, RetryNonIdempotent: true})
+
+	if _, err := client.Authenticate("username", "password"); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if len(bodies) != 2 || bodies[0] == "" || bodies[0] != bodies[1] {
+		t.Errorf("request body should be sent on each attempt, sent %v", bodies)
+	}
+}
+
+func TestUserHttpClient_NoRetryByDefault(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer ts.Close()
+
+	client := New(&HttpRequestBuilderImpl{BaseURL: ts.URL}, &http.Client{})
+	_, err := client.MeContext(context.Background(), "token")
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Error should be service unavailable, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("should be 1 call, made %d", calls)
+	}
+}
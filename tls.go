@@ -0,0 +1,162 @@
+package userclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSConfig configures TLS connections to user service
+type TLSConfig struct {
+	// CAFile is PEM bundle of CAs trusted in addition to the system ones
+	CAFile string
+	// CertFile and KeyFile are PEM client certificate and key for mutual TLS,
+	// they are reloaded when the files are rotated
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name verified in the server certificate
+	ServerName string
+	// MinVersion is tls.VersionTLS12 if not set
+	MinVersion uint16
+}
+
+// Build returns tls.Config, the certificate and the CA bundle are read right away
+func (c TLSConfig) Build() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: c.MinVersion,
+	}
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("both client certificate and key are required")
+		}
+		reloader := &certReloader{certFile: c.CertFile, keyFile: c.KeyFile}
+		if _, err := reloader.certificate(); err != nil {
+			return nil, err
+		}
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.certificate()
+		}
+	}
+
+	return config, nil
+}
+
+// certReloader reads the key pair again when modification time of the files changes
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// certificate returns the current key pair,
+// the previous one is kept if the rotated files can't be loaded, e.g. while they are written
+func (r *certReloader) certificate() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	certInfo, certErr := os.Stat(r.certFile)
+	keyInfo, keyErr := os.Stat(r.keyFile)
+	if certErr == nil && keyErr == nil && r.cert != nil &&
+		certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert = &cert
+	if certErr == nil && keyErr == nil {
+		r.certModTime, r.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+	}
+
+	return r.cert, nil
+}
+
+// WithTLSConfig configures TLS of the transport,
+// it has to be set before options wrapping the transport
+func WithTLSConfig(config TLSConfig) Option {
+	return func(c *HttpClient) error {
+		tlsConfig, err := config.Build()
+		if err != nil {
+			return err
+		}
+		transport, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithProxy sends requests through HTTP proxy, proxy from environment is used by default.
+// It has to be set before options wrapping the transport.
+func WithProxy(proxyURL string) Option {
+	return func(c *HttpClient) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("proxy url %q should be absolute", proxyURL)
+		}
+		transport, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// httpTransport returns own copy of http.Transport of the client
+// so that shared transports like http.DefaultTransport aren't modified
+func (c *HttpClient) httpTransport() (*http.Transport, error) {
+	switch transport := c.requestClient.Transport.(type) {
+	case nil:
+		c.ownTransport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		if transport != c.ownTransport {
+			c.ownTransport = transport.Clone()
+		}
+	default:
+		return nil, fmt.Errorf("transport %T isn't *http.Transport", transport)
+	}
+	c.requestClient.Transport = c.ownTransport
+	return c.ownTransport, nil
+}
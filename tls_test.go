@@ -0,0 +1,179 @@
+package userclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+// writeClientCert writes client key pair signed by the CA into the files
+func (ca *testCA) writeClientCert(t *testing.T, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "orders"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+}
+
+func writeServerCA(t *testing.T, ts *httptest.Server, file string) {
+	cert := ts.Certificate()
+	ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600)
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeServerCA(t, ts, caFile)
+
+	client, err := NewClient(ts.URL, WithTLSConfig(TLSConfig{CAFile: caFile}))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+
+	// certificate of httptest server is valid for example.com
+	client, _ = NewClient(ts.URL, WithTLSConfig(TLSConfig{CAFile: caFile, ServerName: "example.com"}))
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	client, _ = NewClient(ts.URL, WithTLSConfig(TLSConfig{CAFile: caFile, ServerName: "users.internal"}))
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("error should be returned for wrong server name")
+	}
+
+	if _, err := NewClient(ts.URL, WithTLSConfig(TLSConfig{CAFile: filepath.Join(dir, "missing.pem")})); err == nil {
+		t.Error("error should be returned for missing CA bundle")
+	}
+	if _, err := NewClient(ts.URL, WithTLSConfig(TLSConfig{CertFile: caFile})); err == nil {
+		t.Error("error should be returned for certificate without key")
+	}
+}
+
+func TestWithTLSConfig_ClientCertificateReload(t *testing.T) {
+	trusted, untrusted := newTestCA(t), newTestCA(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(trusted.cert)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	writeServerCA(t, ts, caFile)
+	untrusted.writeClientCert(t, certFile, keyFile)
+
+	client, err := NewClient(ts.URL, WithTLSConfig(TLSConfig{
+		CAFile:   caFile,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("error should be returned for untrusted client certificate")
+	}
+
+	trusted.writeClientCert(t, certFile, keyFile)
+	rotated := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, rotated, rotated)
+	os.Chtimes(keyFile, rotated, rotated)
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("error '%s' returned after certificate rotation", err)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			proxied = true
+			if r.URL.Host != "users.internal" {
+				t.Errorf("Wrong proxied host %s", r.URL.Host)
+			}
+		}))
+	defer proxy.Close()
+
+	client, err := NewClient("http://users.internal", WithProxy(proxy.URL))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if !proxied {
+		t.Error("request should be sent through the proxy")
+	}
+
+	if _, err := NewClient("http://users.internal", WithTransportMiddleware(LoggingTransport(NopLogger)), WithProxy(proxy.URL)); err == nil {
+		t.Error("error should be returned for wrapped transport")
+	}
+}
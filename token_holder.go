@@ -1,12 +1,23 @@
 package userclient
 
-import "sync"
+import (
+	"context"
+	"sync"
+	"time"
+)
 
 type TokenHolder interface {
 	GetToken(Client) (string, error)
 	Invalidate()
 }
 
+// ContextTokenHolder is a TokenHolder obtaining new tokens within the context,
+// ClientWithTokenHolder passes the context of the call to it
+type ContextTokenHolder interface {
+	TokenHolder
+	GetTokenContext(ctx context.Context, client Client) (string, error)
+}
+
 // Use this one with token from auth middleware
 // this holder doesn't retry to obtain new token
 type StaticTokenHolder struct {
@@ -21,47 +32,115 @@ func (h *StaticTokenHolder) GetToken(Client) (string, error) {
 	return h.token, nil
 }
 
+func (h *StaticTokenHolder) GetTokenContext(context.Context, Client) (string, error) {
+	return h.token, nil
+}
+
 func (h *StaticTokenHolder) Invalidate() {
 	h.token = ""
 }
 
 // Use this one for your consumers and other backend tasks
-// this holder will automatically request a new token if the old one expired
+// this holder will automatically request a new token if the old one expired,
+// it uses the refresh token when user service provides one and logs in again if the refresh fails
 type InMemoryTokenHolder struct {
 	sync.Mutex
 
 	Username string
 	Password string
 
-	token string
+	login  *LoginResponse
+	logger Logger
 }
 
+// tokenExpiryLeeway is the time before expiry when the token is renewed
+const tokenExpiryLeeway = 30 * time.Second
+
 func NewInMemoryTokenHolder(username, password string) *InMemoryTokenHolder {
 	return &InMemoryTokenHolder{
 		Username: username,
 		Password: password,
+		logger:   NopLogger,
 	}
 }
 
-func (h *InMemoryTokenHolder) GetToken(client Client) (string, error) {
-	if h.token != "" {
-		return h.token, nil
+// SetLogger sets logger of authentications, nil disables logging
+func (h *InMemoryTokenHolder) SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger
+	}
+	h.logger = l
+}
+
+func (h *InMemoryTokenHolder) log() Logger {
+	// the holder may be created without constructor
+	if h.logger == nil {
+		return NopLogger
 	}
+	return h.logger
+}
+
+func (h *InMemoryTokenHolder) GetToken(client Client) (string, error) {
+	return h.GetTokenContext(context.Background(), client)
+}
+
+// GetTokenContext refreshes the token or logs in within ctx if the token is missing or expired
+func (h *InMemoryTokenHolder) GetTokenContext(ctx context.Context, client Client) (string, error) {
 	h.Lock()
 	defer h.Unlock()
-	// another goroutine could update token
-	if h.token != "" {
-		return h.token, nil
+	if h.login != nil && h.login.AccessToken != "" && !h.login.Expired(tokenExpiryLeeway) {
+		return h.login.AccessToken, nil
+	}
+
+	login, err := h.refresh(ctx, client)
+	if err != nil {
+		return "", err
 	}
-	token, err := client.Authenticate(h.Username, h.Password)
-	if token != "" {
-		h.token = token
+	if login != nil {
+		return login.AccessToken, nil
 	}
-	return token, err
+
+	login, err = client.Login(ctx, h.Username, h.Password)
+	if err != nil {
+		h.log().Warn("token holder authentication failed", Fields{"username": h.Username, FieldError: err.Error()})
+		return "", err
+	}
+	h.login = login
+	h.log().Info("token holder authenticated", Fields{"username": h.Username, FieldToken: TokenFingerprint(login.AccessToken)})
+	return login.AccessToken, nil
 }
 
+// refresh renews the token with the refresh token, nil is returned when it isn't possible.
+// The error is returned only when ctx is done, the refresh token is kept then
+func (h *InMemoryTokenHolder) refresh(ctx context.Context, client Client) (*LoginResponse, error) {
+	if h.login == nil || h.login.RefreshToken == "" {
+		return nil, nil
+	}
+	login, err := client.Refresh(ctx, h.login.RefreshToken)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		h.log().Warn("token holder refresh failed", Fields{"username": h.Username, FieldError: err.Error()})
+		h.login = nil
+		return nil, nil
+	}
+	if login.RefreshToken == "" {
+		// user service keeps the refresh token
+		login.RefreshToken = h.login.RefreshToken
+	}
+	h.login = login
+	h.log().Info("token holder refreshed", Fields{"username": h.Username, FieldToken: TokenFingerprint(login.AccessToken)})
+	return login, nil
+}
+
+// Invalidate drops the access token, the refresh token is kept to renew it
 func (h *InMemoryTokenHolder) Invalidate() {
 	h.Lock()
 	defer h.Unlock()
-	h.token = ""
+	if h.login == nil || h.login.AccessToken == "" {
+		return
+	}
+	h.log().Debug("token invalidated", Fields{"username": h.Username, FieldToken: TokenFingerprint(h.login.AccessToken)})
+	h.login.AccessToken = ""
 }
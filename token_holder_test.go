@@ -1,8 +1,10 @@
 package userclient
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func Test_StaticTokenHolder_GetToken(t *testing.T) {
@@ -114,3 +116,86 @@ func Test_InMemoryTokenHolder_Invalidate(t *testing.T) {
 		t.Errorf("client should be called once, but it called %d", clientCalls)
 	}
 }
+
+func Test_InMemoryTokenHolder_Refresh(t *testing.T) {
+	logins, refreshes := 0, 0
+	refreshError := errors.New("refresh token expired")
+	client := &UserClientMock{
+		LoginMock: func(ctx context.Context, username, password string) (*LoginResponse, error) {
+			logins++
+			return &LoginResponse{AccessToken: "login-token", RefreshToken: "refresh-token"}, nil
+		},
+		RefreshMock: func(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+			if refreshToken != "refresh-token" {
+				t.Errorf("wrong refresh token '%s'", refreshToken)
+			}
+			refreshes++
+			if refreshes > 1 {
+				return nil, refreshError
+			}
+			return &LoginResponse{AccessToken: "refreshed-token", ExpiresAt: time.Now().Add(time.Second)}, nil
+		},
+	}
+
+	holder := NewInMemoryTokenHolder("correct", "123")
+	holder.GetToken(client)
+	holder.Invalidate()
+
+	recievedToken, err := holder.GetToken(client)
+	if err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if recievedToken != "refreshed-token" {
+		t.Errorf("token should be refreshed, but it is '%s'", recievedToken)
+	}
+	if logins != 1 || refreshes != 1 {
+		t.Errorf("client should log in and refresh once, but it logged in %d and refreshed %d times", logins, refreshes)
+	}
+
+	// refreshed token expires within the leeway, failed refresh falls back to login
+	recievedToken, err = holder.GetToken(client)
+	if err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if recievedToken != "login-token" {
+		t.Errorf("token should be obtained by login, but it is '%s'", recievedToken)
+	}
+	if logins != 2 || refreshes != 2 {
+		t.Errorf("client should log in and refresh twice, but it logged in %d and refreshed %d times", logins, refreshes)
+	}
+}
+
+func Test_InMemoryTokenHolder_GetTokenContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	client := &UserClientMock{
+		LoginMock: func(ctx context.Context, username, password string) (*LoginResponse, error) {
+			if ctx.Value(key{}) != "value" {
+				return nil, errors.New("wrong context")
+			}
+			return &LoginResponse{AccessToken: "login-token", RefreshToken: "refresh-token"}, nil
+		},
+		RefreshMock: func(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+			return nil, ctx.Err()
+		},
+		MeContextMock: func(ctx context.Context, token string) (*User, error) {
+			return &User{}, nil
+		},
+	}
+
+	holder := NewInMemoryTokenHolder("correct", "123")
+	if _, err := NewClientWithTokenHolder(client, holder).MeContext(ctx); err != nil {
+		t.Errorf("Shouldn't return error, got '%s'", err)
+	}
+
+	// canceled refresh returns the error and keeps the refresh token
+	holder.Invalidate()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := holder.GetTokenContext(canceled, client); err != context.Canceled {
+		t.Errorf("context error should be returned, got '%v'", err)
+	}
+	if holder.login == nil || holder.login.RefreshToken != "refresh-token" {
+		t.Error("refresh token shouldn't be dropped when the context is canceled")
+	}
+}
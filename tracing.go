@@ -0,0 +1,105 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/best-expendables/user-service-client"
+
+// Span attributes set by the client
+const (
+	AttributeMethod      = attribute.Key("userclient.method")
+	AttributeAttempt     = attribute.Key("userclient.attempt")
+	AttributeCacheHit    = attribute.Key("userclient.cache.hit")
+	AttributeUserId      = attribute.Key("enduser.id")
+	attributeHTTPMethod  = attribute.Key("http.request.method")
+	attributeHTTPStatus  = attribute.Key("http.response.status_code")
+	attributeURLPath     = attribute.Key("url.path")
+	attributeServerHost  = attribute.Key("server.address")
+	attributeResendCount = attribute.Key("http.request.resend_count")
+)
+
+// tracer returns tracer of the provider, the global provider is used if it's nil
+func tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// endSpan records the error if any and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TracingClient starts a span for every call of the wrapped client
+type TracingClient struct {
+	*interceptedClient
+
+	tp trace.TracerProvider
+}
+
+// NewTracingClient returns client tracing calls with the provider,
+// the global provider is used if tp is nil
+func NewTracingClient(c ContextClient, tp trace.TracerProvider) *TracingClient {
+	client := &TracingClient{tp: tp}
+	client.interceptedClient = &interceptedClient{client: c, intercept: client.intercept}
+	return client
+}
+
+func (c *TracingClient) intercept(ctx context.Context, method string, call func(ctx context.Context) error) error {
+	ctx, span := tracer(c.tp).Start(ctx, "userclient."+method, trace.WithAttributes(AttributeMethod.String(method)))
+	err := call(ctx)
+	endSpan(span, err)
+	return err
+}
+
+// NewOpenTelemetryHook returns TracingHook starting a client span for every HTTP attempt
+// and injecting trace context into request headers.
+// The global provider is used if tp is nil, W3C trace context and baggage are propagated
+// if propagator is nil.
+func NewOpenTelemetryHook(tp trace.TracerProvider, propagator propagation.TextMapPropagator) TracingHook {
+	if propagator == nil {
+		propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	return func(req *http.Request, attempt int) (*http.Request, func(*http.Response, error)) {
+		ctx, span := tracer(tp).Start(req.Context(), "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attributeHTTPMethod.String(req.Method),
+				attributeURLPath.String(req.URL.Path),
+				attributeServerHost.String(req.URL.Hostname()),
+				AttributeAttempt.Int(attempt),
+			))
+		if attempt > 1 {
+			span.SetAttributes(attributeResendCount.Int(attempt - 1))
+		}
+
+		req = req.Clone(ctx)
+		propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		return req, func(resp *http.Response, err error) {
+			if resp != nil {
+				span.SetAttributes(attributeHTTPStatus.Int(resp.StatusCode))
+			}
+			endSpan(span, err)
+		}
+	}
+}
+
+// WithTracerProvider traces every HTTP attempt with the provider,
+// nil means the global provider. W3C trace context and baggage are propagated.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return WithTracingHook(NewOpenTelemetryHook(tp, nil))
+}
@@ -0,0 +1,159 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracingClient(t *testing.T) {
+	tp, recorder := newTestTracerProvider()
+	client := NewTracingClient(&UserClientMock{
+		FindByIdMock: func(token, userId string) (*User, error) {
+			return nil, ErrNotFound
+		},
+	}, tp)
+
+	client.FindById("token", "1")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Wrong number of spans %d", len(spans))
+	}
+	if spans[0].Name() != "userclient.FindById" {
+		t.Errorf("Wrong span name %q", spans[0].Name())
+	}
+	if len(spans[0].Events()) != 1 {
+		t.Error("error should be recorded")
+	}
+}
+
+func TestOpenTelemetryHook(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Header.Get("traceparent") == "" {
+				t.Error("trace context should be propagated")
+			}
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	defer ts.Close()
+
+	tp, recorder := newTestTracerProvider()
+	client, err := NewClient(ts.URL,
+		WithTracingHook(NewOpenTelemetryHook(tp, propagation.TraceContext{})),
+		WithRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 2}),
+	)
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	if err := client.DeleteUser(context.Background(), "token", "1"); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Wrong number of spans %d", len(spans))
+	}
+	for i, span := range spans {
+		if v, _ := spanAttribute(span, AttributeAttempt); v.AsInt64() != int64(i+1) {
+			t.Errorf("Wrong attempt %v of span %d", v.AsInt64(), i)
+		}
+	}
+	if v, _ := spanAttribute(spans[0], attributeHTTPStatus); v.AsInt64() != http.StatusServiceUnavailable {
+		t.Errorf("Wrong status code %v", v.AsInt64())
+	}
+}
+
+func TestOpenTelemetryHook_DefaultPropagator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("traceparent") == "" {
+				t.Error("trace context should be propagated by default")
+			}
+		}))
+	defer ts.Close()
+
+	tp, _ := newTestTracerProvider()
+	client, err := NewClient(ts.URL, WithTracingHook(NewOpenTelemetryHook(tp, nil)))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	if err := client.DeleteUser(context.Background(), "token", "1"); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+}
+
+func TestCacheClient_Tracing(t *testing.T) {
+	tp, recorder := newTestTracerProvider()
+	client := NewCacheClient(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return &User{}, nil
+		},
+	}, newCachedMock())
+	client.SetTracerProvider(tp)
+
+	client.Me("token")
+	client.Me("token")
+
+	var hits []bool
+	for _, span := range recorder.Ended() {
+		if span.Name() == "userclient.cache.get" {
+			v, _ := spanAttribute(span, AttributeCacheHit)
+			hits = append(hits, v.AsBool())
+		}
+	}
+	if len(hits) != 2 || hits[0] || !hits[1] {
+		t.Errorf("Wrong cache spans %v", hits)
+	}
+}
+
+func TestAuth_Tracing(t *testing.T) {
+	tp, recorder := newTestTracerProvider()
+	m := NewMiddleware(&UserClientMock{
+		MeMock: func(token string) (*User, error) {
+			return &User{Id: "42"}, nil
+		},
+	}, DefaultRetryConfig)
+	m.SetTracerProvider(tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	m.Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(httptest.NewRecorder(), r)
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Wrong number of spans %d", len(spans))
+	}
+	for _, span := range spans {
+		if v, _ := spanAttribute(span, AttributeUserId); v.AsString() != "42" {
+			t.Errorf("Wrong user id %q of span %s", v.AsString(), span.Name())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package userclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// RoundTripperFunc adapts function to http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TransportMiddleware decorates http.RoundTripper
+type TransportMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// WithTransportMiddleware wraps the transport configured so far,
+// the first middleware is the outermost one
+func WithTransportMiddleware(middlewares ...TransportMiddleware) Option {
+	return func(c *HttpClient) error {
+		transport := c.requestClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			transport = middlewares[i](transport)
+		}
+		c.requestClient.Transport = transport
+		return nil
+	}
+}
+
+// HeaderTransport sets headers on every request
+// unless the request already has them
+func HeaderTransport(headers http.Header) TransportMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			missing := false
+			for key := range headers {
+				if _, ok := req.Header[key]; !ok {
+					missing = true
+				}
+			}
+			if !missing {
+				return next.RoundTrip(req)
+			}
+
+			// RoundTripper must not modify the request
+			req = req.Clone(req.Context())
+			for key, values := range headers {
+				if _, ok := req.Header[key]; !ok {
+					req.Header[key] = append([]string(nil), values...)
+				}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// DefaultRequestIDHeader is the header used by RequestIDTransport if none is given
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDTransport propagates request id from the request context,
+// see ContextWithRequestID, header defaults to X-Request-ID
+func RequestIDTransport(header string) TransportMiddleware {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestID := GetRequestIDFromContext(req.Context())
+			if requestID == "" || req.Header.Get(header) != "" {
+				return next.RoundTrip(req)
+			}
+
+			req = req.Clone(req.Context())
+			req.Header.Set(header, requestID)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// LoggingTransport logs every request with its status and duration
+func LoggingTransport(l Logger) TransportMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start)
+
+			fields := requestFields(req)
+			fields["elapsed"] = elapsed
+			if err != nil {
+				fields[FieldError] = err.Error()
+				l.Error("HTTP request failed", fields)
+				return resp, err
+			}
+			fields[FieldStatus] = resp.StatusCode
+			l.Info("HTTP request", fields)
+			return resp, err
+		})
+	}
+}
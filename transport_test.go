@@ -0,0 +1,81 @@
+package userclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithTransportMiddleware(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Request-ID") != "req-1" {
+				t.Errorf("Wrong request id %q", r.Header.Get("X-Request-ID"))
+			}
+			if r.Header.Get("X-Source") != "orders" {
+				t.Errorf("Wrong source header %q", r.Header.Get("X-Source"))
+			}
+		}))
+	defer ts.Close()
+
+	var order []string
+	record := func(name string) TransportMiddleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	client, err := NewClient(ts.URL, WithTransportMiddleware(
+		RequestIDTransport(""),
+		HeaderTransport(http.Header{"X-Source": {"orders"}}),
+		record("inner"),
+	), WithTransportMiddleware(record("outer")))
+	if err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	if err := client.DeleteUser(ctx, "token", "1"); err != nil {
+		t.Errorf("error '%s' returned", err)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("Wrong middleware order %v", order)
+	}
+}
+
+func TestHeaderTransport_NotShared(t *testing.T) {
+	headers := http.Header{"X-Source": {"orders"}}
+	transport := HeaderTransport(headers)(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header["X-Source"][0] += "!"
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+	transport.RoundTrip(req)
+	if headers.Get("X-Source") != "orders" {
+		t.Errorf("Configured headers shouldn't be changed by requests, got %v", headers)
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	client, _ := NewClient(ts.URL, WithTransportMiddleware(LoggingTransport(l)))
+	client.DeleteUser(context.Background(), "token", "1")
+	if len(l.entries) != 1 || l.entries[0].level != "info" {
+		t.Errorf("Wrong log entries %v", l.entries)
+	}
+
+	ts.Close()
+	client.DeleteUser(context.Background(), "token", "1")
+	if len(l.entries) != 2 || l.entries[1].level != "error" {
+		t.Errorf("Wrong log entries %v", l.entries)
+	}
+}
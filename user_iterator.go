@@ -0,0 +1,105 @@
+package userclient
+
+import "context"
+
+const defaultPerPage = 100
+
+type pageFetcher func(ctx context.Context, params ListUsersParams) (*UserPage, error)
+
+// UserIterator walks all pages of users lazily,
+// the next page is requested only when the current one is exhausted.
+// It's NOT safe for concurrent use by multiple goroutines.
+//
+//	it := NewUserIterator(ctx, client, token, ListUsersParams{})
+//	for it.Next() {
+//		user := it.User()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type UserIterator struct {
+	ctx    context.Context
+	fetch  pageFetcher
+	params ListUsersParams
+
+	users []*User
+	user  *User
+	done  bool
+	err   error
+
+	// firstId is id of the first user of the last page
+	firstId string
+}
+
+// NewUserIterator returns iterator over all users starting from the page selected by params,
+// PerPage defaults to 100
+func NewUserIterator(ctx context.Context, c ContextClient, token string, params ListUsersParams) *UserIterator {
+	return newUserIterator(ctx, params, func(ctx context.Context, params ListUsersParams) (*UserPage, error) {
+		return c.ListUsers(ctx, token, params)
+	})
+}
+
+// NewSearchUserIterator returns iterator over all users matching filter
+// starting from the page selected by params, PerPage defaults to 100
+func NewSearchUserIterator(ctx context.Context, c ContextClient, token string, filter UserFilter, params ListUsersParams) *UserIterator {
+	return newUserIterator(ctx, params, func(ctx context.Context, params ListUsersParams) (*UserPage, error) {
+		return c.SearchUsers(ctx, token, filter, params)
+	})
+}
+
+func newUserIterator(ctx context.Context, params ListUsersParams, fetch pageFetcher) *UserIterator {
+	if params.PerPage <= 0 {
+		params.PerPage = defaultPerPage
+	}
+	return &UserIterator{
+		ctx:    ctx,
+		fetch:  fetch,
+		params: params,
+	}
+}
+
+// Next advances the iterator to the next user,
+// it returns false when there are no more users or an error occurred
+func (it *UserIterator) Next() bool {
+	for len(it.users) == 0 {
+		if it.done || it.err != nil {
+			it.user = nil
+			return false
+		}
+		it.fetchPage()
+	}
+
+	it.user, it.users = it.users[0], it.users[1:]
+	return true
+}
+
+// User returns the current user
+func (it *UserIterator) User() *User {
+	return it.user
+}
+
+// Err returns the first error occurred during iteration
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+func (it *UserIterator) fetchPage() {
+	page, err := it.fetch(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	// a server ignoring the page parameter returns the same page again
+	if len(page.Users) > 0 {
+		if it.firstId != "" && page.Users[0].Id == it.firstId {
+			it.done = true
+			return
+		}
+		it.firstId = page.Users[0].Id
+	}
+
+	var hasNext bool
+	it.users = page.Users
+	it.params, hasNext = page.next(it.params)
+	it.done = !hasNext
+}
@@ -0,0 +1,91 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUserIterator_Pages(t *testing.T) {
+	var requested []ListUsersParams
+	client := &UserClientMock{
+		ListUsersMock: func(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+			requested = append(requested, params)
+			switch params.Page {
+			case 0:
+				return &UserPage{Users: []*User{{Id: "1"}, {Id: "2"}}}, nil
+			case 2:
+				return &UserPage{Users: []*User{{Id: "3"}}}, nil
+			case 3:
+				return &UserPage{}, nil
+			}
+			return nil, errors.New("unexpected page")
+		},
+	}
+
+	it := NewUserIterator(context.Background(), client, "token", ListUsersParams{PerPage: 2})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.User().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[2] != "3" {
+		t.Errorf("wrong users iterated: %v", ids)
+	}
+	// short page doesn't end paging without total
+	if len(requested) != 3 {
+		t.Errorf("should be 3 requests, made %d", len(requested))
+	}
+}
+
+func TestUserIterator_Cursor(t *testing.T) {
+	client := &UserClientMock{
+		ListUsersMock: func(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+			switch params.Cursor {
+			case "":
+				return &UserPage{Users: []*User{{Id: "1"}}, NextCursor: "c1"}, nil
+			case "c1":
+				return &UserPage{Users: []*User{{Id: "2"}}}, nil
+			}
+			return nil, errors.New("unexpected cursor")
+		},
+	}
+
+	it := NewUserIterator(context.Background(), client, "token", ListUsersParams{})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("error '%s' returned", err)
+	}
+	if count != 2 {
+		t.Errorf("should iterate 2 users, iterated %d", count)
+	}
+}
+
+func TestUserIterator_Error(t *testing.T) {
+	clientError := errors.New("client error")
+	client := &UserClientMock{
+		ListUsersMock: func(ctx context.Context, token string, params ListUsersParams) (*UserPage, error) {
+			if params.Page == 0 {
+				return &UserPage{Users: []*User{{Id: "1"}}, Page: 1, PerPage: 1, Total: 2}, nil
+			}
+			return nil, clientError
+		},
+	}
+
+	it := NewUserIterator(context.Background(), client, "token", ListUsersParams{})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != clientError {
+		t.Errorf("error '%s' should be returned, but it is '%s'", clientError, it.Err())
+	}
+	if count != 1 {
+		t.Errorf("should iterate 1 user, iterated %d", count)
+	}
+}